@@ -91,118 +91,6 @@ fm.general.sorting = {
   ignore_diacritics = true,
 }
 
-fm.modes.customs["go-to"] = {
-  name = "go-to",
-  key_bindings = {
-    default = {
-      help = "cancel",
-      messages = {
-        {
-          name = "SwitchMode",
-          args = {
-            "default",
-          },
-        },
-      },
-    },
-    on_keys = {
-      ["~"] = {
-        help = "Home",
-        messages = {
-          {
-            name = "ChangeDirectory",
-            args = {
-              "/Users/dinhhuy258",
-            },
-          },
-          {
-            name = "SwitchMode",
-            args = {
-              "default",
-            },
-          },
-        },
-      },
-      w = {
-        help = "Workspace",
-        messages = {
-          {
-            name = "ChangeDirectory",
-            args = {
-              "/Users/dinhhuy258/Workspace",
-            },
-          },
-          {
-            name = "SwitchMode",
-            args = {
-              "default",
-            },
-          },
-        },
-      },
-      D = {
-        help = "Documents",
-        messages = {
-          {
-            name = "ChangeDirectory",
-            args = {
-              "/Users/dinhhuy258/Documents",
-            },
-          },
-          {
-            name = "SwitchMode",
-            args = {
-              "default",
-            },
-          },
-        },
-      },
-      d = {
-        help = "Downloads",
-        messages = {
-          {
-            name = "ChangeDirectory",
-            args = {
-              "/Users/dinhhuy258/Downloads",
-            },
-          },
-          {
-            name = "SwitchMode",
-            args = {
-              "default",
-            },
-          },
-        },
-      },
-      h = {
-        help = "Desktop",
-        messages = {
-          {
-            name = "ChangeDirectory",
-            args = {
-              "/Users/dinhhuy258/Desktop",
-            },
-          },
-          {
-            name = "SwitchMode",
-            args = {
-              "default",
-            },
-          },
-        },
-      },
-      ["ctrl+c"] = {
-        help = "quit",
-        messages = {
-          {
-            name = "Quit",
-          },
-        },
-      },
-    },
-  },
-}
-
 fm.modes.customs.yarn = {
   name = "yarn",
   key_bindings = {
@@ -275,14 +163,6 @@ fm.modes.customs.yarn = {
           },
         },
       },
-      ["ctrl+c"] = {
-        help = "quit",
-        messages = {
-          {
-            name = "Quit",
-          },
-        },
-      },
     },
   },
 }
@@ -338,14 +218,6 @@ fm.modes.customs["mark-save"] = {
           },
         },
       },
-      ["ctrl+c"] = {
-        help = "quit",
-        messages = {
-          {
-            name = "Quit",
-          },
-        },
-      },
     },
   },
 }
@@ -406,14 +278,6 @@ fm.modes.customs["mark-load"] = {
           },
         },
       },
-      ["ctrl+c"] = {
-        help = "quit",
-        messages = {
-          {
-            name = "Quit",
-          },
-        },
-      },
     },
   },
 }
@@ -531,25 +395,66 @@ fm.modes.customs.open = {
           },
         },
       },
-      ["ctrl+c"] = {
-        help = "quit",
-        messages = {
-          {
-            name = "Quit",
-          },
-        },
+    },
+  },
+}
+
+-- The "g" chords go to the usual directories, next to the builtin "g g" (top) and "g h" (home)
+fm.modes.builtins.default.key_bindings.on_keys["g ~"] = {
+  help = "go to Home",
+  messages = {
+    {
+      name = "ChangeDirectory",
+      args = {
+        "/Users/dinhhuy258",
       },
     },
   },
 }
 
-fm.modes.builtins.default.key_bindings.on_keys["g"] = {
-  help = "go to",
+fm.modes.builtins.default.key_bindings.on_keys["g w"] = {
+  help = "go to Workspace",
   messages = {
     {
-      name = "SwitchMode",
+      name = "ChangeDirectory",
       args = {
-        "go-to",
+        "/Users/dinhhuy258/Workspace",
+      },
+    },
+  },
+}
+
+fm.modes.builtins.default.key_bindings.on_keys["g D"] = {
+  help = "go to Documents",
+  messages = {
+    {
+      name = "ChangeDirectory",
+      args = {
+        "/Users/dinhhuy258/Documents",
+      },
+    },
+  },
+}
+
+fm.modes.builtins.default.key_bindings.on_keys["g d"] = {
+  help = "go to Downloads",
+  messages = {
+    {
+      name = "ChangeDirectory",
+      args = {
+        "/Users/dinhhuy258/Downloads",
+      },
+    },
+  },
+}
+
+fm.modes.builtins.default.key_bindings.on_keys["g t"] = {
+  help = "go to Desktop",
+  messages = {
+    {
+      name = "ChangeDirectory",
+      args = {
+        "/Users/dinhhuy258/Desktop",
       },
     },
   },
@@ -648,4 +553,12 @@ fm.modes.builtins.default.key_bindings.on_keys["ctrl+d"] = {
       },
     },
   },
-}
\ No newline at end of file
+}
+
+fm.on("directory_changed", function(ev)
+  if ev.path:find("/Downloads$") then
+    return {
+      name = "SortByDateModified",
+    }
+  end
+end)
@@ -13,7 +13,8 @@ require (
 	github.com/rivo/uniseg v0.4.7
 	github.com/yuin/gluamapper v0.0.0-20150323120927-d836955830e7
 	github.com/yuin/gopher-lua v0.0.0-20220504180219-658193537a64
-	golang.org/x/text v0.3.8
+	golang.org/x/image v0.25.0
+	golang.org/x/text v0.23.0
 )
 
 require (
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/dinhhuy258/fm/pkg/actions"
 	"github.com/dinhhuy258/fm/pkg/config"
 	"github.com/dinhhuy258/fm/pkg/config/lua"
 	"github.com/dinhhuy258/fm/pkg/pipe"
@@ -18,6 +19,9 @@ var version = "unversioned"
 
 func main() {
 	showVersion := flag.Bool("version", false, "Print the current version")
+	checkConfig := flag.Bool("check-config", false, "Validate the config file and exit")
+	dumpConfig := flag.Bool("dump-config", false, "Print the effective config as lua and exit")
+	dumpDefaults := flag.Bool("defaults", false, "Use the default config with --dump-config")
 	flag.Parse()
 
 	if *showVersion {
@@ -25,6 +29,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *dumpConfig && *dumpDefaults {
+		if err := config.DumpConfig(os.Stdout, config.GetDefaultConfig()); err != nil {
+			log.Fatalf("failed to dump config: %v", err)
+		}
+
+		os.Exit(0)
+	}
+
 	// Initialize Lua configuration
 	luaEngine := lua.NewLua()
 	defer luaEngine.Close()
@@ -34,6 +46,18 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
+	if *checkConfig {
+		os.Exit(runConfigCheck(luaEngine))
+	}
+
+	if *dumpConfig {
+		if err := config.DumpConfig(os.Stdout, config.AppConfig); err != nil {
+			log.Fatalf("failed to dump config: %v", err)
+		}
+
+		os.Exit(0)
+	}
+
 	// Initialize pipe for external commands
 	pipe, err := pipe.NewPipe()
 	if err != nil {
@@ -42,7 +66,7 @@ func main() {
 	defer pipe.StopWatcher()
 
 	// Create the Bubble Tea model
-	model := tui.NewModel(pipe)
+	model := tui.NewModel(pipe, luaEngine)
 
 	// Create the Bubble Tea program
 	program := tea.NewProgram(
@@ -58,7 +82,38 @@ func main() {
 	})
 
 	// Run the program
-	if _, err := program.Run(); err != nil {
+	finalModel, err := program.Run()
+	if err != nil {
 		log.Fatalf("Error running Bubble Tea program: %v", err)
 	}
+
+	// Notifications can't be seen once fm exited
+	if finalModel, ok := finalModel.(tui.Model); ok {
+		if err := finalModel.GetQuitError(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// runConfigCheck validates the loaded config, prints the problems found and returns the exit code
+func runConfigCheck(luaEngine *lua.Lua) int {
+	configPath := "default config"
+	if configFilePath := config.GetConfigFilePath(); configFilePath.IsPresent() {
+		configPath = *configFilePath.Get()
+	}
+
+	diagnostics := config.Validate(config.AppConfig, luaEngine, actions.ValidateMessage)
+	if len(diagnostics) == 0 {
+		fmt.Printf("%s: OK\n", configPath)
+
+		return 0
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Printf("%s: %s\n", configPath, diagnostic)
+	}
+
+	fmt.Printf("%d problem(s) found\n", len(diagnostics))
+
+	return 1
 }
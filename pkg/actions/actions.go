@@ -2,204 +2,30 @@ package actions
 
 import (
 	"fmt"
-	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/dinhhuy258/fm/pkg/config"
 )
 
-// ActionHandlerFunc defines the signature for action handlers
-type ActionHandlerFunc func(message *config.MessageConfig, originalKey tea.KeyMsg) tea.Cmd
-
 // ActionHandler handles execution of config messages
-type ActionHandler struct {
-	actionMap map[string]ActionHandlerFunc
-}
+type ActionHandler struct{}
 
 // NewActionHandler creates a new action handler
 func NewActionHandler() *ActionHandler {
-	ah := &ActionHandler{}
-	ah.initActionMap()
-
-	return ah
+	return &ActionHandler{}
 }
 
-// initActionMap initializes the action handler map
-func (ah *ActionHandler) initActionMap() {
-	ah.actionMap = map[string]ActionHandlerFunc{
-		// Core messages
-		"SwitchMode": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return ModeChangedMessage{Mode: message.Args[0]}
-			}
-		},
-		"Quit": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return tea.Quit
-		},
-		"Null": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return nil
-		},
-
-		// Navigation messages
-		"ChangeDirectory": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return ChangeDirectoryMessage{Path: message.Args[0]}
-			}
-		},
-		"FocusPath": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return FocusPathMessage{Path: message.Args[0]}
-			}
-		},
-		"FocusByIndex": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				index, _ := strconv.Atoi(message.Args[0])
-
-				return FocusByIndexMessage{Index: index}
-			}
-		},
-		"FocusNext": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return NavigationMessage{Action: NavigationActionNext}
-			}
-		},
-		"FocusPrevious": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return NavigationMessage{Action: NavigationActionPrevious}
-			}
-		},
-		"FocusFirst": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return NavigationMessage{Action: NavigationActionFirst}
-			}
-		},
-		"FocusLast": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return NavigationMessage{Action: NavigationActionLast}
-			}
-		},
-		"Enter": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return NavigationMessage{Action: NavigationActionEnter}
-			}
-		},
-		"Back": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return NavigationMessage{Action: NavigationActionBack}
-			}
-		},
-
-		// Selection messages
-		"ToggleSelection": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SelectionMessage{Action: SelectionActionToggle}
-			}
-		},
-		"ClearSelection": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SelectionMessage{Action: SelectionActionClear}
-			}
-		},
-		"SelectAll": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SelectionMessage{Action: SelectionActionAll}
-			}
-		},
-		"ToggleSelectionByPath": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return ToggleSelectionByPathMessage{Path: message.Args[0]}
-			}
-		},
-
-		// Sorting messages
-		"SortByName": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SortingMessage{SortType: SortTypeName}
-			}
-		},
-		"SortBySize": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SortingMessage{SortType: SortTypeSize}
-			}
-		},
-		"SortByDateModified": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SortingMessage{SortType: SortTypeDate}
-			}
-		},
-		"SortByExtension": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SortingMessage{SortType: SortTypeExtension}
-			}
-		},
-		"SortByDirFirst": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SortingMessage{SortType: SortTypeDirFirst}
-			}
-		},
-		"ReverseSort": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SortingMessage{SortType: SortTypeReverse}
-			}
-		},
-
-		// Bash execution
-		"BashExec": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return BashExecMessage{Script: message.Args[0]}
-			}
-		},
-		"BashExecSilently": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return BashExecSilentlyMessage{Script: message.Args[0]}
-			}
-		},
+// ValidateMessage checks that the message is known and its arguments match the message spec
+func ValidateMessage(message *config.MessageConfig) error {
+	spec, exists := LookupMessage(message.Name)
+	if !exists {
+		return fmt.Errorf("unknown message %q", message.Name)
+	}
 
-		// Input and logging
-		"SetInputBuffer": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return SetInputBufferMessage{Value: message.Args[0]}
-			}
-		},
-		"UpdateInputBufferFromKey": func(_ *config.MessageConfig, originalKey tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return UpdateInputBufferFromKeyMessage{Key: originalKey}
-			}
-		},
-		"LogSuccess": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return LogMessage{Level: LogLevelSuccess, Message: message.Args[0]}
-			}
-		},
-		"LogError": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return LogMessage{Level: LogLevelError, Message: message.Args[0]}
-			}
-		},
-		"LogInfo": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return LogMessage{Level: LogLevelInfo, Message: message.Args[0]}
-			}
-		},
-		"LogWarning": func(message *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return LogMessage{Level: LogLevelWarning, Message: message.Args[0]}
-			}
-		},
+	_, err := spec.ParseArgs(message.Args)
 
-		// UI control
-		"ToggleHidden": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return UIMessage{Action: UIActionToggleHidden}
-			}
-		},
-		"Refresh": func(_ *config.MessageConfig, _ tea.KeyMsg) tea.Cmd {
-			return func() tea.Msg {
-				return UIMessage{Action: UIActionRefresh}
-			}
-		},
-	}
+	return err
 }
 
 // ExecuteMessages executes a list of messages from config sequentially
@@ -222,19 +48,38 @@ func (ah *ActionHandler) ExecuteMessages(
 	return tea.Sequence(cmds...)
 }
 
-// ExecuteMessage executes a single message
+// ExecuteMessage executes a single message.
+// The arguments are validated and parsed before dispatching, invalid messages are reported
+// as notifications instead of being executed.
 func (ah *ActionHandler) ExecuteMessage(
 	message *config.MessageConfig,
 	originalKey tea.KeyMsg,
 ) tea.Cmd {
-	if action, exists := ah.actionMap[message.Name]; exists {
-		return action(message, originalKey)
+	spec, exists := LookupMessage(message.Name)
+	if !exists {
+		return func() tea.Msg {
+			return LogMessage{
+				Level:   LogLevelWarning,
+				Message: fmt.Sprintf("Unknown message type: %s", message.Name),
+			}
+		}
 	}
 
-	return func() tea.Msg {
-		return LogMessage{
-			Level:   LogLevelWarning,
-			Message: fmt.Sprintf("Unknown message type: %s", message.Name),
+	args, err := spec.ParseArgs(message.Args)
+	if err != nil {
+		return func() tea.Msg {
+			return LogMessage{
+				Level:   LogLevelError,
+				Message: fmt.Sprintf("Invalid message: %v", err),
+			}
 		}
 	}
+
+	if spec.create == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		return spec.create(args, originalKey)
+	}
 }
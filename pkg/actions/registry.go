@@ -0,0 +1,588 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dinhhuy258/fm/pkg/fs"
+)
+
+// ArgType represents the type of a message argument
+type ArgType string
+
+const (
+	// ArgTypeString accepts any string
+	ArgTypeString ArgType = "string"
+	// ArgTypeInt accepts an integer
+	ArgTypeInt ArgType = "int"
+	// ArgTypePath accepts a file path, a leading ~ is expanded to the home directory
+	ArgTypePath ArgType = "path"
+)
+
+// ArgSpec describes an argument of a message
+type ArgSpec struct {
+	Name string
+	Type ArgType
+	// Choices lists the accepted values, empty means any value of the type
+	Choices []string
+}
+
+// Args contains the parsed arguments of a message
+type Args []any
+
+// String returns the string argument at the given index
+func (a Args) String(i int) string {
+	value, _ := a[i].(string)
+
+	return value
+}
+
+// Int returns the integer argument at the given index
+func (a Args) Int(i int) int {
+	value, _ := a[i].(int)
+
+	return value
+}
+
+// MessageSpec describes a message which can be sent to fm from key bindings, hooks or the pipe
+type MessageSpec struct {
+	Name string
+	Args []ArgSpec
+	Help string
+
+	// Repeatable messages are executed count times when a key binding is preceded by a count
+	Repeatable bool
+	// KeepsCount messages do not consume the count, e.g. the messages building the count
+	KeepsCount bool
+
+	// create builds the tea message from the parsed arguments and the key which triggered it,
+	// a nil create means the message does nothing
+	create func(args Args, originalKey tea.KeyMsg) tea.Msg
+}
+
+// Usage returns the message name followed by its arguments, e.g. FocusPath <path>
+func (ms *MessageSpec) Usage() string {
+	var builder strings.Builder
+	builder.WriteString(ms.Name)
+
+	for _, arg := range ms.Args {
+		builder.WriteString(" <" + arg.Name + ">")
+	}
+
+	return builder.String()
+}
+
+// ParseArgs validates the number of arguments and converts them to their declared types
+func (ms *MessageSpec) ParseArgs(args []string) (Args, error) {
+	if len(args) != len(ms.Args) {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", ms.Name, len(ms.Args), len(args))
+	}
+
+	parsedArgs := make(Args, 0, len(args))
+
+	for i, arg := range args {
+		spec := ms.Args[i]
+
+		if len(spec.Choices) > 0 && !slices.Contains(spec.Choices, arg) {
+			return nil, fmt.Errorf("%s: argument <%s> must be one of %s, got %q",
+				ms.Name, spec.Name, strings.Join(spec.Choices, ", "), arg)
+		}
+
+		switch spec.Type {
+		case ArgTypeInt:
+			value, err := strconv.Atoi(strings.TrimSpace(arg))
+			if err != nil {
+				return nil, fmt.Errorf("%s: argument <%s> must be an integer, got %q", ms.Name, spec.Name, arg)
+			}
+
+			parsedArgs = append(parsedArgs, value)
+		case ArgTypePath:
+			parsedArgs = append(parsedArgs, expandHome(arg))
+		default:
+			parsedArgs = append(parsedArgs, arg)
+		}
+	}
+
+	return parsedArgs, nil
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(homeDir, path[1:])
+}
+
+// messageSpecs contains all messages supported by fm
+var messageSpecs = []*MessageSpec{
+	// Core messages
+	{
+		Name: "SwitchMode",
+		Args: []ArgSpec{{Name: "mode", Type: ArgTypeString}},
+		Help: "switch to the given mode",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return ModeChangedMessage{Mode: args.String(0)}
+		},
+	},
+	{
+		Name: "Quit",
+		Help: "quit fm",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return QuitMessage{}
+		},
+	},
+	{
+		Name: "Null",
+		Help: "do nothing",
+	},
+	{
+		Name: "ReloadConfig",
+		Help: "reload the config file",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return ReloadConfigMessage{}
+		},
+	},
+	{
+		Name: "SetTheme",
+		Args: []ArgSpec{{Name: "name", Type: ArgTypeString}},
+		Help: "switch to the given theme of fm.themes, an empty name removes the theme",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return SetThemeMessage{Name: args.String(0)}
+		},
+	},
+
+	// Navigation messages
+	{
+		Name: "ChangeDirectory",
+		Args: []ArgSpec{{Name: "path", Type: ArgTypePath}},
+		Help: "change the current directory",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return ChangeDirectoryMessage{Path: args.String(0)}
+		},
+	},
+	{
+		Name: "FocusPath",
+		Args: []ArgSpec{{Name: "path", Type: ArgTypePath}},
+		Help: "focus the given path, changing directory if needed",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return FocusPathMessage{Path: args.String(0)}
+		},
+	},
+	{
+		Name: "FocusByIndex",
+		Args: []ArgSpec{{Name: "index", Type: ArgTypeInt}},
+		Help: "focus the entry at the given zero-based index",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return FocusByIndexMessage{Index: args.Int(0)}
+		},
+	},
+	{
+		Name:       "FocusNext",
+		Help:       "focus the next entry",
+		Repeatable: true,
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return NavigationMessage{Action: NavigationActionNext}
+		},
+	},
+	{
+		Name:       "FocusPrevious",
+		Help:       "focus the previous entry",
+		Repeatable: true,
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return NavigationMessage{Action: NavigationActionPrevious}
+		},
+	},
+	{
+		Name: "FocusFirst",
+		Help: "focus the first entry",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return NavigationMessage{Action: NavigationActionFirst}
+		},
+	},
+	{
+		Name: "FocusLast",
+		Help: "focus the last entry",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return NavigationMessage{Action: NavigationActionLast}
+		},
+	},
+	{
+		Name: "Enter",
+		Help: "enter the focused directory",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return NavigationMessage{Action: NavigationActionEnter}
+		},
+	},
+	{
+		Name:       "Back",
+		Help:       "go to the parent directory",
+		Repeatable: true,
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return NavigationMessage{Action: NavigationActionBack}
+		},
+	},
+
+	// Selection messages
+	{
+		Name: "ToggleSelection",
+		Help: "toggle the selection of the focused entry",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionToggle}
+		},
+	},
+	{
+		Name: "ClearSelection",
+		Help: "clear the selection",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionClear}
+		},
+	},
+	{
+		Name: "SelectAll",
+		Help: "select all entries of the current directory",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionAll}
+		},
+	},
+	{
+		Name: "InvertSelection",
+		Help: "invert the selection of the entries of the current directory",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionInvert}
+		},
+	},
+	{
+		Name: "SelectByPattern",
+		Args: []ArgSpec{{Name: "pattern", Type: ArgTypeString}},
+		Help: "select the entries whose name matches a glob or a /regex/",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionByPattern, Pattern: args.String(0)}
+		},
+	},
+	{
+		Name: "DeselectByPattern",
+		Args: []ArgSpec{{Name: "pattern", Type: ArgTypeString}},
+		Help: "deselect the entries whose name matches a glob or a /regex/",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionDeselectByPattern, Pattern: args.String(0)}
+		},
+	},
+	{
+		Name: "SelectSameExtension",
+		Help: "select the entries with the extension of the focused entry",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionSameExtension}
+		},
+	},
+	{
+		Name: "SelectByType",
+		Args: []ArgSpec{{
+			Name:    "type",
+			Type:    ArgTypeString,
+			Choices: []string{fs.EntryTypeFile, fs.EntryTypeDir, fs.EntryTypeSymlink},
+		}},
+		Help: "select the entries of the given type: file, dir or symlink",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionByType, EntryType: args.String(0)}
+		},
+	},
+	{
+		Name: "SelectRangeStart",
+		Help: "start a range selection at the focused entry",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionRangeStart}
+		},
+	},
+	{
+		Name: "SelectRangeCommit",
+		Help: "add the entries of the range to the selection",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionRangeCommit}
+		},
+	},
+	{
+		Name: "SelectRangeCancel",
+		Help: "discard the range selection",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SelectionMessage{Action: SelectionActionRangeCancel}
+		},
+	},
+	{
+		Name: "ToggleSelectionByPath",
+		Args: []ArgSpec{{Name: "path", Type: ArgTypePath}},
+		Help: "toggle the selection of the given path",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return ToggleSelectionByPathMessage{Path: args.String(0)}
+		},
+	},
+
+	// Sorting messages
+	{
+		Name: "SortByName",
+		Help: "sort by name",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SortingMessage{SortType: SortTypeName}
+		},
+	},
+	{
+		Name: "SortBySize",
+		Help: "sort by size",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SortingMessage{SortType: SortTypeSize}
+		},
+	},
+	{
+		Name: "SortByDateModified",
+		Help: "sort by date modified",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SortingMessage{SortType: SortTypeDate}
+		},
+	},
+	{
+		Name: "SortByExtension",
+		Help: "sort by extension",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SortingMessage{SortType: SortTypeExtension}
+		},
+	},
+	{
+		Name: "SortByDirFirst",
+		Help: "sort directories first",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SortingMessage{SortType: SortTypeDirFirst}
+		},
+	},
+	{
+		Name: "ReverseSort",
+		Help: "reverse the sort order",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return SortingMessage{SortType: SortTypeReverse}
+		},
+	},
+
+	// Count prefix
+	{
+		Name:       "UpdateCountFromKey",
+		Help:       "append the pressed digit to the count",
+		KeepsCount: true,
+		create: func(_ Args, originalKey tea.KeyMsg) tea.Msg {
+			return UpdateCountFromKeyMessage{Key: originalKey}
+		},
+	},
+	{
+		Name: "ClearCount",
+		Help: "clear the count",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return ClearCountMessage{}
+		},
+	},
+
+	// Bash execution
+	{
+		Name: "BashExec",
+		Args: []ArgSpec{{Name: "script", Type: ArgTypeString}},
+		Help: "run a bash script in the terminal",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return BashExecMessage{Script: args.String(0)}
+		},
+	},
+	{
+		Name: "BashExecSilently",
+		Args: []ArgSpec{{Name: "script", Type: ArgTypeString}},
+		Help: "run a bash script in the background",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return BashExecSilentlyMessage{Script: args.String(0)}
+		},
+	},
+
+	// Input and logging
+	{
+		Name: "SetInputBuffer",
+		Args: []ArgSpec{{Name: "value", Type: ArgTypeString}},
+		Help: "show the input with the given value",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return SetInputBufferMessage{Value: args.String(0)}
+		},
+	},
+	{
+		Name: "UpdateInputBufferFromKey",
+		Help: "apply the pressed key to the input",
+		create: func(_ Args, originalKey tea.KeyMsg) tea.Msg {
+			return UpdateInputBufferFromKeyMessage{Key: originalKey}
+		},
+	},
+	{
+		Name: "LogSuccess",
+		Args: []ArgSpec{{Name: "message", Type: ArgTypeString}},
+		Help: "show a success notification",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return LogMessage{Level: LogLevelSuccess, Message: args.String(0)}
+		},
+	},
+	{
+		Name: "LogError",
+		Args: []ArgSpec{{Name: "message", Type: ArgTypeString}},
+		Help: "show an error notification",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return LogMessage{Level: LogLevelError, Message: args.String(0)}
+		},
+	},
+	{
+		Name: "LogInfo",
+		Args: []ArgSpec{{Name: "message", Type: ArgTypeString}},
+		Help: "show an info notification",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return LogMessage{Level: LogLevelInfo, Message: args.String(0)}
+		},
+	},
+	{
+		Name: "LogWarning",
+		Args: []ArgSpec{{Name: "message", Type: ArgTypeString}},
+		Help: "show a warning notification",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return LogMessage{Level: LogLevelWarning, Message: args.String(0)}
+		},
+	},
+
+	// Mouse messages
+	{
+		Name: "FocusMouseEntry",
+		Help: "focus the entry under the mouse",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return MouseMessage{Action: MouseActionFocusEntry}
+		},
+	},
+	{
+		Name: "SortByMouseColumn",
+		Help: "sort by the column under the mouse, or reverse if it is already sorted by it",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return MouseMessage{Action: MouseActionSortByColumn}
+		},
+	},
+	{
+		Name: "ChangeDirectoryToMousePath",
+		Help: "change to the directory of the current path under the mouse",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return MouseMessage{Action: MouseActionChangeDirectory}
+		},
+	},
+
+	// UI control
+	{
+		Name: "ToggleHidden",
+		Help: "toggle hidden files",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionToggleHidden}
+		},
+	},
+	{
+		Name: "Refresh",
+		Help: "reload the current directory",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionRefresh}
+		},
+	},
+	{
+		Name: "ToggleSelectionPanel",
+		Help: "show or hide the panel listing the selected paths of every directory",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionToggleSelectionPanel}
+		},
+	},
+	{
+		Name: "ToggleLogViewer",
+		Help: "show or hide the history of the notifications",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionToggleLogViewer}
+		},
+	},
+	{
+		Name: "TogglePreview",
+		Help: "show or hide the preview of the focused entry",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionTogglePreview}
+		},
+	},
+	{
+		Name: "ToggleDirectorySizes",
+		Help: "compute or stop computing the recursive sizes of the directories in the background",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionToggleDirectorySizes}
+		},
+	},
+	{
+		Name: "DiskUsage",
+		Help: "scan the disk usage of the current directory and list its entries by size",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionDiskUsage}
+		},
+	},
+	{
+		Name: "ExitDiskUsage",
+		Help: "cancel the disk usage scan or leave the disk usage listing",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionExitDiskUsage}
+		},
+	},
+	{
+		Name: "ShowEntryInfo",
+		Help: "show the information about the focused entry, including the error reading it",
+		create: func(_ Args, _ tea.KeyMsg) tea.Msg {
+			return UIMessage{Action: UIActionShowEntryInfo}
+		},
+	},
+	{
+		Name: "ExportLog",
+		Args: []ArgSpec{{Name: "path", Type: ArgTypePath}},
+		Help: "write the history of the notifications to the given file",
+		create: func(args Args, _ tea.KeyMsg) tea.Msg {
+			return ExportLogMessage{Path: args.String(0)}
+		},
+	},
+}
+
+// registry maps message names to their specs
+var registry = newRegistry(messageSpecs)
+
+// newRegistry indexes the message specs by name
+func newRegistry(specs []*MessageSpec) map[string]*MessageSpec {
+	r := make(map[string]*MessageSpec, len(specs))
+	for _, spec := range specs {
+		r[spec.Name] = spec
+	}
+
+	return r
+}
+
+// LookupMessage returns the spec of the message with the given name
+func LookupMessage(name string) (*MessageSpec, bool) {
+	spec, exists := registry[name]
+
+	return spec, exists
+}
+
+// GetMessageSpecs returns the specs of all messages sorted by name
+func GetMessageSpecs() []*MessageSpec {
+	specs := make([]*MessageSpec, len(messageSpecs))
+	copy(specs, messageSpecs)
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+
+	return specs
+}
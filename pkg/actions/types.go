@@ -13,6 +13,22 @@ type ModeChangedMessage struct {
 	Mode string
 }
 
+// QuitMessage requests quitting the application
+type QuitMessage struct{}
+
+// ReloadConfigMessage requests reloading the config file
+type ReloadConfigMessage struct{}
+
+// SetThemeMessage requests switching to a theme of the config
+type SetThemeMessage struct {
+	Name string
+}
+
+// ExportLogMessage requests writing the notification history to a file
+type ExportLogMessage struct {
+	Path string
+}
+
 // FocusPathMessage requests focusing on a specific path
 type FocusPathMessage struct {
 	Path string
@@ -54,6 +70,14 @@ type UpdateInputBufferFromKeyMessage struct {
 	Key tea.KeyMsg
 }
 
+// UpdateCountFromKeyMessage appends the digit of the last key press to the count
+type UpdateCountFromKeyMessage struct {
+	Key tea.KeyMsg
+}
+
+// ClearCountMessage clears the count
+type ClearCountMessage struct{}
+
 // NavigationAction represents navigation actions.
 type NavigationAction string
 
@@ -80,11 +104,37 @@ const (
 	SelectionActionToggle SelectionAction = "toggle"
 	SelectionActionClear  SelectionAction = "clear"
 	SelectionActionAll    SelectionAction = "all"
+	SelectionActionInvert SelectionAction = "invert"
+
+	SelectionActionByPattern         SelectionAction = "by_pattern"
+	SelectionActionDeselectByPattern SelectionAction = "deselect_by_pattern"
+	SelectionActionSameExtension     SelectionAction = "same_extension"
+	SelectionActionByType            SelectionAction = "by_type"
+
+	SelectionActionRangeStart  SelectionAction = "range_start"
+	SelectionActionRangeCommit SelectionAction = "range_commit"
+	SelectionActionRangeCancel SelectionAction = "range_cancel"
 )
 
 // SelectionMessage handles selection actions
 type SelectionMessage struct {
-	Action SelectionAction
+	Action    SelectionAction
+	Pattern   string // Used with "by_pattern" and "deselect_by_pattern" actions
+	EntryType string // Used with "by_type" action
+}
+
+// MouseAction represents actions on the target of the last mouse event.
+type MouseAction string
+
+const (
+	MouseActionFocusEntry      MouseAction = "focus_entry"
+	MouseActionSortByColumn    MouseAction = "sort_by_column"
+	MouseActionChangeDirectory MouseAction = "change_directory"
+)
+
+// MouseMessage handles actions on the target of the last mouse event
+type MouseMessage struct {
+	Action MouseAction
 }
 
 // UIAction represents UI control actions.
@@ -93,6 +143,14 @@ type UIAction string
 const (
 	UIActionToggleHidden UIAction = "toggle_hidden"
 	UIActionRefresh      UIAction = "refresh"
+
+	UIActionToggleSelectionPanel UIAction = "toggle_selection_panel"
+	UIActionToggleLogViewer      UIAction = "toggle_log_viewer"
+	UIActionTogglePreview        UIAction = "toggle_preview"
+	UIActionShowEntryInfo        UIAction = "show_entry_info"
+	UIActionToggleDirectorySizes UIAction = "toggle_directory_sizes"
+	UIActionDiskUsage            UIAction = "disk_usage"
+	UIActionExitDiskUsage        UIAction = "exit_disk_usage"
 )
 
 // UIMessage handles UI control actions
@@ -5,14 +5,6 @@ var defaultModeConfig = ModeConfig{
 	Name: "default",
 	KeyBindings: KeyBindingsConfig{
 		OnKeys: map[string]*ActionConfig{
-			"ctrl+c": {
-				Help: "quit",
-				Messages: []*MessageConfig{
-					{
-						Name: "Quit",
-					},
-				},
-			},
 			"j": {
 				Help: "down",
 				Messages: []*MessageConfig{
@@ -61,6 +53,23 @@ var defaultModeConfig = ModeConfig{
 					},
 				},
 			},
+			"g g": {
+				Help: "go to top",
+				Messages: []*MessageConfig{
+					{
+						Name: "FocusFirst",
+					},
+				},
+			},
+			"g h": {
+				Help: "go home",
+				Messages: []*MessageConfig{
+					{
+						Name: "ChangeDirectory",
+						Args: []string{"~"},
+					},
+				},
+			},
 			"n": {
 				Help: "new file",
 				Messages: []*MessageConfig{
@@ -285,8 +294,120 @@ var defaultModeConfig = ModeConfig{
 					},
 				},
 			},
+			"+": {
+				Help: "select by pattern",
+				Messages: []*MessageConfig{
+					{
+						Name: "SwitchMode",
+						Args: []string{"select-by-pattern"},
+					},
+					{
+						Name: "SetInputBuffer",
+						Args: []string{""},
+					},
+				},
+			},
+			"-": {
+				Help: "deselect by pattern",
+				Messages: []*MessageConfig{
+					{
+						Name: "SwitchMode",
+						Args: []string{"deselect-by-pattern"},
+					},
+					{
+						Name: "SetInputBuffer",
+						Args: []string{""},
+					},
+				},
+			},
+			"*": {
+				Help: "invert selection",
+				Messages: []*MessageConfig{
+					{
+						Name: "InvertSelection",
+					},
+				},
+			},
+			"S": {
+				Help: "selection panel",
+				Messages: []*MessageConfig{
+					{
+						Name: "ToggleSelectionPanel",
+					},
+				},
+			},
+			"L": {
+				Help: "notification history",
+				Messages: []*MessageConfig{
+					{
+						Name: "ToggleLogViewer",
+					},
+				},
+			},
+			"u": {
+				Help: "disk usage",
+				Messages: []*MessageConfig{
+					{
+						Name: "DiskUsage",
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"disk-usage"},
+					},
+				},
+			},
+			"D": {
+				Help: "directory sizes",
+				Messages: []*MessageConfig{
+					{
+						Name: "ToggleDirectorySizes",
+					},
+				},
+			},
+			"i": {
+				Help: "entry info",
+				Messages: []*MessageConfig{
+					{
+						Name: "ShowEntryInfo",
+					},
+				},
+			},
+			"P": {
+				Help: "preview",
+				Messages: []*MessageConfig{
+					{
+						Name: "TogglePreview",
+					},
+				},
+			},
+			"V": {
+				Help: "visual",
+				Messages: []*MessageConfig{
+					{
+						Name: "SwitchMode",
+						Args: []string{"visual"},
+					},
+					{
+						Name: "SelectRangeStart",
+					},
+				},
+			},
+			";": {
+				Help: "fm command",
+				Messages: []*MessageConfig{
+					{
+						Name: "SwitchMode",
+						Args: []string{"fm-command"},
+					},
+					{
+						Name: "SetInputBuffer",
+						Args: []string{""},
+					},
+				},
+			},
 		},
 		OnNumber: &ActionConfig{
+			Help: "go to index",
 			Messages: []*MessageConfig{
 				{
 					Name: "SwitchMode",
@@ -301,22 +422,72 @@ var defaultModeConfig = ModeConfig{
 				},
 			},
 		},
+		OnMouse: map[string]*ActionConfig{
+			MouseEventClick: {
+				Help: "focus",
+				Messages: []*MessageConfig{
+					{
+						Name: "FocusMouseEntry",
+					},
+				},
+			},
+			MouseEventDoubleClick: {
+				Help: "enter",
+				Messages: []*MessageConfig{
+					{
+						Name: "FocusMouseEntry",
+					},
+					{
+						Name: "Enter",
+					},
+				},
+			},
+			MouseEventWheelUp: {
+				Help: "up",
+				Messages: []*MessageConfig{
+					{
+						Name: "FocusPrevious",
+					},
+				},
+			},
+			MouseEventWheelDown: {
+				Help: "down",
+				Messages: []*MessageConfig{
+					{
+						Name: "FocusNext",
+					},
+				},
+			},
+			MouseEventHeaderClick: {
+				Help: "sort by column",
+				Messages: []*MessageConfig{
+					{
+						Name: "SortByMouseColumn",
+					},
+				},
+			},
+			MouseEventPathClick: {
+				Help: "change directory",
+				Messages: []*MessageConfig{
+					{
+						Name: "ChangeDirectoryToMousePath",
+					},
+				},
+			},
+		},
 	},
 }
 
 // newFileModeConfig is the configuration for the new file builtin mode.
 var newFileModeConfig = ModeConfig{
 	Name: "new-file",
+	Input: &InputConfig{
+		Prompt:      "new file: ",
+		Placeholder: "name, end with / to create a directory",
+		Completion:  CompletionPath,
+	},
 	KeyBindings: KeyBindingsConfig{
 		OnKeys: map[string]*ActionConfig{
-			"ctrl+c": {
-				Help: "quit",
-				Messages: []*MessageConfig{
-					{
-						Name: "Quit",
-					},
-				},
-			},
 			"enter": {
 				Help: "new file",
 				Messages: []*MessageConfig{
@@ -375,16 +546,13 @@ var newFileModeConfig = ModeConfig{
 // renameModeConfig is the configuration for the rename builtin mode.
 var renameModeConfig = ModeConfig{
 	Name: "rename",
+	Input: &InputConfig{
+		Prompt:      "rename: ",
+		Placeholder: "new name",
+		Completion:  CompletionPath,
+	},
 	KeyBindings: KeyBindingsConfig{
 		OnKeys: map[string]*ActionConfig{
-			"ctrl+c": {
-				Help: "quit",
-				Messages: []*MessageConfig{
-					{
-						Name: "Quit",
-					},
-				},
-			},
 			"enter": {
 				Help: "rename",
 				Messages: []*MessageConfig{
@@ -396,7 +564,7 @@ var renameModeConfig = ModeConfig{
 
 							if [ -z "${new_name}" ]; then
 								echo SwitchMode "'"default"'" >> "${FM_PIPE_MSG_IN:?}"
-							elif [ -e "${new_name:?}" ]; then
+							elif [ -e "${new_name:?}" ] || [ -L "${new_name:?}" ]; then
 								echo SwitchMode "'"default"'" >> "${FM_PIPE_MSG_IN:?}"
                 echo LogError "'"${new_name} already exists"'" >> "${FM_PIPE_MSG_IN:?}"
               else
@@ -435,14 +603,6 @@ var sortModeConfig = ModeConfig{
 	Name: "sort",
 	KeyBindings: KeyBindingsConfig{
 		OnKeys: map[string]*ActionConfig{
-			"ctrl+c": {
-				Help: "quit",
-				Messages: []*MessageConfig{
-					{
-						Name: "Quit",
-					},
-				},
-			},
 			"d": {
 				Help: "dir first",
 				Messages: []*MessageConfig{
@@ -530,16 +690,14 @@ var sortModeConfig = ModeConfig{
 // commandModeConfig is the configuration for the command builtin mode.
 var commandModeConfig = ModeConfig{
 	Name: "command",
+	Input: &InputConfig{
+		Prompt:      "$ ",
+		Placeholder: "shell command",
+		Completion:  CompletionPath,
+		History:     true,
+	},
 	KeyBindings: KeyBindingsConfig{
 		OnKeys: map[string]*ActionConfig{
-			"ctrl+c": {
-				Help: "quit",
-				Messages: []*MessageConfig{
-					{
-						Name: "Quit",
-					},
-				},
-			},
 			"enter": {
 				Help: "execute",
 				Messages: []*MessageConfig{
@@ -577,19 +735,62 @@ var commandModeConfig = ModeConfig{
 	},
 }
 
-// goToIndexModeConfig is the configuration for the go to index builtin mode.
-var goToIndexModeConfig = ModeConfig{
-	Name: "go-to-index",
+// fmCommandModeConfig is the configuration for the fm command builtin mode.
+var fmCommandModeConfig = ModeConfig{
+	Name: "fm-command",
+	Input: &InputConfig{
+		Prompt:      "fm: ",
+		Placeholder: "message, e.g. FocusPath ~/Downloads",
+		Completion:  CompletionMessage,
+		History:     true,
+	},
 	KeyBindings: KeyBindingsConfig{
 		OnKeys: map[string]*ActionConfig{
-			"ctrl+c": {
-				Help: "quit",
+			"enter": {
+				Help: "execute",
+				Messages: []*MessageConfig{
+					{
+						Name: "BashExecSilently",
+						Args: []string{`
+							echo "${FM_INPUT_BUFFER}" >> "${FM_PIPE_MSG_IN:?}"
+						`},
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+			"esc": {
+				Help: "cancel",
 				Messages: []*MessageConfig{
 					{
-						Name: "Quit",
+						Name: "SwitchMode",
+						Args: []string{"default"},
 					},
 				},
 			},
+		},
+		Default: &ActionConfig{
+			Messages: []*MessageConfig{
+				{
+					Name: "UpdateInputBufferFromKey",
+				},
+			},
+		},
+	},
+}
+
+// goToIndexModeConfig is the configuration for the go to index builtin mode.
+var goToIndexModeConfig = ModeConfig{
+	Name: "go-to-index",
+	Input: &InputConfig{
+		Prompt:      "go to index: ",
+		Placeholder: "index",
+		Validate:    "^[0-9]+$",
+	},
+	KeyBindings: KeyBindingsConfig{
+		OnKeys: map[string]*ActionConfig{
 			"enter": {
 				Help: "go to index",
 				Messages: []*MessageConfig{
@@ -640,12 +841,183 @@ var goToIndexModeConfig = ModeConfig{
 	},
 }
 
+// selectByPatternModeConfig is the configuration for the select by pattern builtin mode.
+var selectByPatternModeConfig = ModeConfig{
+	Name: "select-by-pattern",
+	Input: &InputConfig{
+		Prompt:      "select: ",
+		Placeholder: "glob or /regex/",
+		Preview:     PreviewMatchCount,
+	},
+	KeyBindings: KeyBindingsConfig{
+		OnKeys: map[string]*ActionConfig{
+			"enter": {
+				Help: "select",
+				Messages: []*MessageConfig{
+					{
+						Name: "BashExecSilently",
+						Args: []string{`
+							pattern="${FM_INPUT_BUFFER}"
+							if [ -n "${pattern}" ]; then
+								echo SelectByPattern "'"${pattern}"'" >> "${FM_PIPE_MSG_IN:?}"
+							fi
+						`},
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+			"esc": {
+				Help: "cancel",
+				Messages: []*MessageConfig{
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+		},
+		Default: &ActionConfig{
+			Messages: []*MessageConfig{
+				{
+					Name: "UpdateInputBufferFromKey",
+				},
+			},
+		},
+	},
+}
+
+// deselectByPatternModeConfig is the configuration for the deselect by pattern builtin mode.
+var deselectByPatternModeConfig = ModeConfig{
+	Name: "deselect-by-pattern",
+	Input: &InputConfig{
+		Prompt:      "deselect: ",
+		Placeholder: "glob or /regex/",
+		Preview:     PreviewMatchCount,
+	},
+	KeyBindings: KeyBindingsConfig{
+		OnKeys: map[string]*ActionConfig{
+			"enter": {
+				Help: "deselect",
+				Messages: []*MessageConfig{
+					{
+						Name: "BashExecSilently",
+						Args: []string{`
+							pattern="${FM_INPUT_BUFFER}"
+							if [ -n "${pattern}" ]; then
+								echo DeselectByPattern "'"${pattern}"'" >> "${FM_PIPE_MSG_IN:?}"
+							fi
+						`},
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+			"esc": {
+				Help: "cancel",
+				Messages: []*MessageConfig{
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+		},
+		Default: &ActionConfig{
+			Messages: []*MessageConfig{
+				{
+					Name: "UpdateInputBufferFromKey",
+				},
+			},
+		},
+	},
+}
+
+// visualModeConfig is the configuration for the visual builtin mode, the entries between the
+// range anchor and the focus are selected while moving.
+var visualModeConfig = ModeConfig{
+	Name:    "visual",
+	Extends: "default",
+	KeyBindings: KeyBindingsConfig{
+		OnKeys: map[string]*ActionConfig{
+			"V": {
+				Help: "select range",
+				Messages: []*MessageConfig{
+					{
+						Name: "SelectRangeCommit",
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+			"esc": {
+				Help: "cancel",
+				Messages: []*MessageConfig{
+					{
+						Name: "SelectRangeCancel",
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+		},
+	},
+}
+
+// diskUsageModeConfig is the configuration for the disk usage builtin mode, the entries of the
+// scanned directories are listed by disk usage.
+var diskUsageModeConfig = ModeConfig{
+	Name:    "disk-usage",
+	Extends: "default",
+	KeyBindings: KeyBindingsConfig{
+		OnKeys: map[string]*ActionConfig{
+			"esc": {
+				Help: "leave disk usage",
+				Messages: []*MessageConfig{
+					{
+						Name: "ExitDiskUsage",
+					},
+					{
+						Name: "SwitchMode",
+						Args: []string{"default"},
+					},
+				},
+			},
+		},
+	},
+}
+
+// globalKeyBindings are the key bindings available in every mode unless the mode overrides them.
+var globalKeyBindings = map[string]*ActionConfig{
+	"ctrl+c": {
+		Help: "quit",
+		Messages: []*MessageConfig{
+			{
+				Name: "Quit",
+			},
+		},
+	},
+}
+
 // builtinModeConfigs is a map of mode names to their configs.
 var builtinModeConfigs = map[string]*ModeConfig{
-	"default":     &defaultModeConfig,
-	"new-file":    &newFileModeConfig,
-	"rename":      &renameModeConfig,
-	"sort":        &sortModeConfig,
-	"command":     &commandModeConfig,
-	"go-to-index": &goToIndexModeConfig,
+	"default":             &defaultModeConfig,
+	"new-file":            &newFileModeConfig,
+	"rename":              &renameModeConfig,
+	"sort":                &sortModeConfig,
+	"command":             &commandModeConfig,
+	"fm-command":          &fmCommandModeConfig,
+	"go-to-index":         &goToIndexModeConfig,
+	"visual":              &visualModeConfig,
+	"disk-usage":          &diskUsageModeConfig,
+	"select-by-pattern":   &selectByPatternModeConfig,
+	"deselect-by-pattern": &deselectByPatternModeConfig,
 }
@@ -53,6 +53,8 @@ type KeyBindingsConfig struct {
 	OnKeys   map[string]*ActionConfig `mapper:"on_keys"`
 	OnNumber *ActionConfig            `mapper:"on_number"`
 	Default  *ActionConfig            `mapper:"default"`
+	// OnMouse maps mouse events (see MouseEvents) to actions
+	OnMouse map[string]*ActionConfig `mapper:"on_mouse"`
 }
 
 // toLuaTable convert to LuaTable object
@@ -66,6 +68,13 @@ func (kbc *KeyBindingsConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lu
 
 	tbl.RawSetString("on_keys", onKeyTbl)
 
+	onMouseTbl := luaState.NewTable()
+	for event, actionConfig := range kbc.OnMouse {
+		onMouseTbl.RawSetString(event, actionConfig.toLuaTable(luaState))
+	}
+
+	tbl.RawSetString("on_mouse", onMouseTbl)
+
 	if kbc.OnNumber != nil {
 		tbl.RawSetString("on_number", kbc.OnNumber.toLuaTable(luaState))
 	} else {
@@ -81,9 +90,51 @@ func (kbc *KeyBindingsConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lu
 	return tbl
 }
 
+// InputConfig represents the config for the input of a mode.
+type InputConfig struct {
+	Prompt      string `mapper:"prompt"`
+	Placeholder string `mapper:"placeholder"`
+	// CharLimit is the maximum length of the input, 0 means no limit
+	CharLimit int `mapper:"char_limit"`
+	// Validate is a regex string or a lua function, see NewInputValidator
+	Validate any `mapper:"validate"`
+	// Completion is the completion triggered by tab: "path", "message" or empty for none
+	Completion string `mapper:"completion"`
+	// History enables the input history of the mode
+	History bool `mapper:"history"`
+	// Preview is shown next to the input while typing: "match_count" or empty for none
+	Preview string `mapper:"preview"`
+}
+
+// toLuaTable convert to LuaTable object
+func (ic *InputConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	tbl.RawSetString("prompt", gopher_lua.LString(ic.Prompt))
+	tbl.RawSetString("placeholder", gopher_lua.LString(ic.Placeholder))
+	tbl.RawSetString("char_limit", gopher_lua.LNumber(ic.CharLimit))
+	tbl.RawSetString("completion", gopher_lua.LString(ic.Completion))
+	tbl.RawSetString("history", gopher_lua.LBool(ic.History))
+	tbl.RawSetString("preview", gopher_lua.LString(ic.Preview))
+
+	switch validate := ic.Validate.(type) {
+	case string:
+		tbl.RawSetString("validate", gopher_lua.LString(validate))
+	case gopher_lua.LValue:
+		tbl.RawSetString("validate", validate)
+	default:
+		tbl.RawSetString("validate", gopher_lua.LNil)
+	}
+
+	return tbl
+}
+
 // ModeConfig represents the config for the mode.
 type ModeConfig struct {
-	Name        string            `mapper:"name"`
+	Name string `mapper:"name"`
+	// Extends is the name of the mode whose key bindings and input are inherited
+	Extends     string            `mapper:"extends"`
+	Input       *InputConfig      `mapper:"input"`
 	KeyBindings KeyBindingsConfig `mapper:"key_bindings"`
 }
 
@@ -92,6 +143,14 @@ func (mc *ModeConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable
 	tbl := luaState.NewTable()
 
 	tbl.RawSetString("name", gopher_lua.LString(mc.Name))
+	tbl.RawSetString("extends", gopher_lua.LString(mc.Extends))
+
+	if mc.Input != nil {
+		tbl.RawSetString("input", mc.Input.toLuaTable(luaState))
+	} else {
+		tbl.RawSetString("input", gopher_lua.LNil)
+	}
+
 	tbl.RawSetString("key_bindings", mc.KeyBindings.toLuaTable(luaState))
 
 	return tbl
@@ -148,6 +207,7 @@ type NodeTypesConfig struct {
 	Directory        *NodeTypeConfig            `mapper:"directory"`
 	FileSymlink      *NodeTypeConfig            `mapper:"file_symlink"`
 	DirectorySymlink *NodeTypeConfig            `mapper:"directory_symlink"`
+	BrokenSymlink    *NodeTypeConfig            `mapper:"broken_symlink"`
 	Extensions       map[string]*NodeTypeConfig `mapper:"extensions"`
 	Specials         map[string]*NodeTypeConfig `mapper:"specials"`
 }
@@ -180,6 +240,12 @@ func (ntc *NodeTypesConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.
 		tbl.RawSetString("directory_symlink", gopher_lua.LNil)
 	}
 
+	if ntc.BrokenSymlink != nil {
+		tbl.RawSetString("broken_symlink", ntc.BrokenSymlink.toLuaTable(luaState))
+	} else {
+		tbl.RawSetString("broken_symlink", gopher_lua.LNil)
+	}
+
 	extensionTbl := luaState.NewTable()
 	for ext, extConfig := range ntc.Extensions {
 		extensionTbl.RawSetString(ext, extConfig.toLuaTable(luaState))
@@ -254,6 +320,12 @@ type ExplorerTableHeaderConfig struct {
 	Name       string       `mapper:"name"`
 	Percentage int          `mapper:"percentage"`
 	Style      *StyleConfig `mapper:"style"`
+
+	// MinWidth is the width below which the column is widened at the expense of the name column.
+	// When the table is too narrow for the min widths, the columns with the lowest priority are
+	// dropped first. The name column is never dropped.
+	MinWidth int `mapper:"min_width"`
+	Priority int `mapper:"priority"`
 }
 
 // toLuaTable convert to LuaTable object
@@ -262,6 +334,8 @@ func (ethc *ExplorerTableHeaderConfig) toLuaTable(luaState *gopher_lua.LState) *
 
 	tbl.RawSetString("name", gopher_lua.LString(ethc.Name))
 	tbl.RawSetString("percentage", gopher_lua.LNumber(ethc.Percentage))
+	tbl.RawSetString("min_width", gopher_lua.LNumber(ethc.MinWidth))
+	tbl.RawSetString("priority", gopher_lua.LNumber(ethc.Priority))
 
 	if ethc.Style != nil {
 		tbl.RawSetString("style", ethc.Style.toLuaTable(luaState))
@@ -276,11 +350,18 @@ func (ethc *ExplorerTableHeaderConfig) toLuaTable(luaState *gopher_lua.LState) *
 type ExplorerTableConfig struct {
 	IndexHeader *ExplorerTableHeaderConfig `mapper:"index_header"`
 	NameHeader  *ExplorerTableHeaderConfig `mapper:"name_header"`
+	// SizeHeader and DateHeader are optional columns, they are shown when they are set
+	SizeHeader *ExplorerTableHeaderConfig `mapper:"size_header"`
+	DateHeader *ExplorerTableHeaderConfig `mapper:"date_header"`
+	// UsageHeader is the column of the disk usage, it is only shown by the disk usage mode and its
+	// percentage is not part of the total of the other columns
+	UsageHeader *ExplorerTableHeaderConfig `mapper:"usage_header"`
 
 	DefaultUI        *DefaultUIConfig `mapper:"default_ui"`
 	FocusUI          *UIConfig        `mapper:"focus_ui"`
 	SelectionUI      *UIConfig        `mapper:"selection_ui"`
 	FocusSelectionUI *UIConfig        `mapper:"focus_selection_ui"`
+	RangeUI          *UIConfig        `mapper:"range_ui"`
 
 	FirstEntryPrefix string `mapper:"first_entry_prefix"`
 	EntryPrefix      string `mapper:"entry_prefix"`
@@ -303,6 +384,18 @@ func (etc *ExplorerTableConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_
 		tbl.RawSetString("name_header", gopher_lua.LNil)
 	}
 
+	if etc.SizeHeader != nil {
+		tbl.RawSetString("size_header", etc.SizeHeader.toLuaTable(luaState))
+	}
+
+	if etc.DateHeader != nil {
+		tbl.RawSetString("date_header", etc.DateHeader.toLuaTable(luaState))
+	}
+
+	if etc.UsageHeader != nil {
+		tbl.RawSetString("usage_header", etc.UsageHeader.toLuaTable(luaState))
+	}
+
 	if etc.DefaultUI != nil {
 		tbl.RawSetString("default_ui", etc.DefaultUI.toLuaTable(luaState))
 	} else {
@@ -327,6 +420,12 @@ func (etc *ExplorerTableConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_
 		tbl.RawSetString("focus_selection_ui", gopher_lua.LNil)
 	}
 
+	if etc.RangeUI != nil {
+		tbl.RawSetString("range_ui", etc.RangeUI.toLuaTable(luaState))
+	} else {
+		tbl.RawSetString("range_ui", gopher_lua.LNil)
+	}
+
 	tbl.RawSetString("first_entry_prefix", gopher_lua.LString(etc.FirstEntryPrefix))
 	tbl.RawSetString("entry_prefix", gopher_lua.LString(etc.EntryPrefix))
 	tbl.RawSetString("last_entry_prefix", gopher_lua.LString(etc.LastEntryPrefix))
@@ -334,6 +433,71 @@ func (etc *ExplorerTableConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_
 	return tbl
 }
 
+// LayoutConfig represents the rules adapting the layout to small terminals
+type LayoutConfig struct {
+	// Border shows the border and its padding around the main view
+	Border bool `mapper:"border"`
+	// BorderMinWidth and BorderMinHeight are the terminal sizes below which the border is hidden
+	BorderMinWidth  int `mapper:"border_min_width"`
+	BorderMinHeight int `mapper:"border_min_height"`
+	// CompactHeaderHeight is the terminal height below which the header is collapsed to one line
+	CompactHeaderHeight int `mapper:"compact_header_height"`
+}
+
+// toLuaTable convert to LuaTable object
+func (lc *LayoutConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	tbl.RawSetString("border", gopher_lua.LBool(lc.Border))
+	tbl.RawSetString("border_min_width", gopher_lua.LNumber(lc.BorderMinWidth))
+	tbl.RawSetString("border_min_height", gopher_lua.LNumber(lc.BorderMinHeight))
+	tbl.RawSetString("compact_header_height", gopher_lua.LNumber(lc.CompactHeaderHeight))
+
+	return tbl
+}
+
+// PreviewConfig represents the config of the preview of the focused entry
+type PreviewConfig struct {
+	// Visible shows the preview on start, it is toggled with the TogglePreview message
+	Visible bool `mapper:"visible"`
+	// WidthPercentage is the percentage of the width of the explorer used by the preview
+	WidthPercentage int `mapper:"width_percentage"`
+	// ImageProtocol is the way images are drawn: auto, kitty, half_block or none
+	ImageProtocol string `mapper:"image_protocol"`
+	// MaxImageSize is the size in bytes above which images are not previewed, 0 for no limit
+	MaxImageSize int `mapper:"max_image_size"`
+}
+
+// toLuaTable convert to LuaTable object
+func (pc *PreviewConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	tbl.RawSetString("visible", gopher_lua.LBool(pc.Visible))
+	tbl.RawSetString("width_percentage", gopher_lua.LNumber(pc.WidthPercentage))
+	tbl.RawSetString("image_protocol", gopher_lua.LString(pc.ImageProtocol))
+	tbl.RawSetString("max_image_size", gopher_lua.LNumber(pc.MaxImageSize))
+
+	return tbl
+}
+
+// DirectorySizeConfig represents the config of the recursive sizes of the directories
+type DirectorySizeConfig struct {
+	// Enabled computes the sizes on start, it is toggled with the ToggleDirectorySizes message
+	Enabled bool `mapper:"enabled"`
+	// Concurrency is the maximum number of directories walked at the same time
+	Concurrency int `mapper:"concurrency"`
+}
+
+// toLuaTable convert to LuaTable object
+func (dc *DirectorySizeConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	tbl.RawSetString("enabled", gopher_lua.LBool(dc.Enabled))
+	tbl.RawSetString("concurrency", gopher_lua.LNumber(dc.Concurrency))
+
+	return tbl
+}
+
 // SortingConfig represents the config for sorting
 type SortingConfig struct {
 	SortType         string `mapper:"sort_type"`
@@ -389,14 +553,37 @@ func (fu *FrameUI) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
 type GeneralConfig struct {
 	FrameUI *FrameUI `mapper:"frame_ui"`
 
+	// TitleStyle is the style of the current path, InfoStyle the style of the line below it
+	TitleStyle  *StyleConfig `mapper:"title_style"`
+	InfoStyle   *StyleConfig `mapper:"info_style"`
+	FooterStyle *StyleConfig `mapper:"footer_style"`
+
+	// HeaderFormat is the line below the current path, FooterFormat the last line
+	HeaderFormat *StatusLineConfig `mapper:"header_format"`
+	FooterFormat *StatusLineConfig `mapper:"footer_format"`
+
 	LogInfoUI    *UIConfig `mapper:"log_info_ui"`
 	LogWarningUI *UIConfig `mapper:"log_warning_ui"`
 	LogErrorUI   *UIConfig `mapper:"log_error_ui"`
 
 	ExplorerTable *ExplorerTableConfig `mapper:"explorer_table"`
+	Layout        *LayoutConfig        `mapper:"layout"`
+	Preview       *PreviewConfig       `mapper:"preview"`
+	DirectorySize *DirectorySizeConfig `mapper:"directory_size"`
 
-	Sorting    *SortingConfig `mapper:"sorting"`
-	ShowHidden bool           `mapper:"show_hidden"`
+	Sorting     *SortingConfig `mapper:"sorting"`
+	ShowHidden  bool           `mapper:"show_hidden"`
+	WatchConfig bool           `mapper:"watch_config"`
+
+	// KeySequenceTimeout is the time in milliseconds to wait for the next key of a key sequence
+	KeySequenceTimeout int `mapper:"key_sequence_timeout"`
+	// WhichKeyDelay is the time in milliseconds after which the keys which can follow the pending
+	// keys are shown, 0 disables the popup
+	WhichKeyDelay int `mapper:"which_key_delay"`
+	// HistorySize is the maximum number of input history entries kept per mode
+	HistorySize int `mapper:"history_size"`
+	// Theme is the name of the theme in fm.themes, empty for no theme
+	Theme string `mapper:"theme"`
 }
 
 // toLuaTable convert to LuaTable object
@@ -409,6 +596,31 @@ func (gc *GeneralConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTa
 		tbl.RawSetString("frame_ui", gopher_lua.LNil)
 	}
 
+	styles := map[string]*StyleConfig{
+		"title_style":  gc.TitleStyle,
+		"info_style":   gc.InfoStyle,
+		"footer_style": gc.FooterStyle,
+	}
+	for name, style := range styles {
+		if style != nil {
+			tbl.RawSetString(name, style.toLuaTable(luaState))
+		} else {
+			tbl.RawSetString(name, gopher_lua.LNil)
+		}
+	}
+
+	statusLines := map[string]*StatusLineConfig{
+		"header_format": gc.HeaderFormat,
+		"footer_format": gc.FooterFormat,
+	}
+	for name, statusLine := range statusLines {
+		if statusLine != nil {
+			tbl.RawSetString(name, statusLine.toLuaTable(luaState))
+		} else {
+			tbl.RawSetString(name, gopher_lua.LNil)
+		}
+	}
+
 	if gc.LogInfoUI != nil {
 		tbl.RawSetString("log_info_ui", gc.LogInfoUI.toLuaTable(luaState))
 	} else {
@@ -433,6 +645,24 @@ func (gc *GeneralConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTa
 		tbl.RawSetString("explorer_table", gopher_lua.LNil)
 	}
 
+	if gc.Layout != nil {
+		tbl.RawSetString("layout", gc.Layout.toLuaTable(luaState))
+	} else {
+		tbl.RawSetString("layout", gopher_lua.LNil)
+	}
+
+	if gc.Preview != nil {
+		tbl.RawSetString("preview", gc.Preview.toLuaTable(luaState))
+	} else {
+		tbl.RawSetString("preview", gopher_lua.LNil)
+	}
+
+	if gc.DirectorySize != nil {
+		tbl.RawSetString("directory_size", gc.DirectorySize.toLuaTable(luaState))
+	} else {
+		tbl.RawSetString("directory_size", gopher_lua.LNil)
+	}
+
 	if gc.Sorting != nil {
 		tbl.RawSetString("sorting", gc.Sorting.toLuaTable(luaState))
 	} else {
@@ -440,20 +670,34 @@ func (gc *GeneralConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTa
 	}
 
 	tbl.RawSetString("show_hidden", gopher_lua.LBool(gc.ShowHidden))
+	tbl.RawSetString("watch_config", gopher_lua.LBool(gc.WatchConfig))
+	tbl.RawSetString("key_sequence_timeout", gopher_lua.LNumber(gc.KeySequenceTimeout))
+	tbl.RawSetString("which_key_delay", gopher_lua.LNumber(gc.WhichKeyDelay))
+	tbl.RawSetString("history_size", gopher_lua.LNumber(gc.HistorySize))
+	tbl.RawSetString("theme", gopher_lua.LString(gc.Theme))
 
 	return tbl
 }
 
 // ModesConfig represents the config for the custom and builtin modes.
 type ModesConfig struct {
-	Customs  map[string]*ModeConfig `mapper:"customs"`
-	Builtins map[string]*ModeConfig `mapper:"builtins"`
+	// Global contains the key bindings of all modes, modes can override them
+	Global   map[string]*ActionConfig `mapper:"global"`
+	Customs  map[string]*ModeConfig   `mapper:"customs"`
+	Builtins map[string]*ModeConfig   `mapper:"builtins"`
 }
 
 // toLuaTable convert to LuaTable object
 func (m *ModesConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
 	tbl := luaState.NewTable()
 
+	globalTbl := luaState.NewTable()
+	for key, actionConfig := range m.Global {
+		globalTbl.RawSetString(key, actionConfig.toLuaTable(luaState))
+	}
+
+	tbl.RawSetString("global", globalTbl)
+
 	customTbl := luaState.NewTable()
 	for name, modeConfig := range m.Customs {
 		customTbl.RawSetString(name, modeConfig.toLuaTable(luaState))
@@ -473,9 +717,14 @@ func (m *ModesConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable
 
 // Config represents the config for the application.
 type Config struct {
-	General   *GeneralConfig   `mapper:"general"`
-	Modes     *ModesConfig     `mapper:"modes"`
-	NodeTypes *NodeTypesConfig `mapper:"node_types"`
+	General   *GeneralConfig          `mapper:"general"`
+	Modes     *ModesConfig            `mapper:"modes"`
+	NodeTypes *NodeTypesConfig        `mapper:"node_types"`
+	Themes    map[string]*ThemeConfig `mapper:"themes"`
+
+	// baseTheme contains the parts of the config changed by themes, as they were before
+	// the first theme was applied
+	baseTheme *ThemeConfig
 }
 
 // toLuaTable convert to LuaTable object
@@ -500,6 +749,13 @@ func (c *Config) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
 		tbl.RawSetString("node_types", gopher_lua.LNil)
 	}
 
+	themesTbl := luaState.NewTable()
+	for name, themeConfig := range c.Themes {
+		themesTbl.RawSetString(name, themeConfig.toLuaTable(luaState))
+	}
+
+	tbl.RawSetString("themes", themesTbl)
+
 	return tbl
 }
 
@@ -507,17 +763,20 @@ var AppConfig *Config
 
 // LoadConfig loads the config from config file and default config then merges them.
 func LoadConfig(lua *lua.Lua) error {
-	configFilePath := getConfigFilePath()
+	configFilePath := GetConfigFilePath()
 
 	if configFilePath.IsPresent() {
-		userConfig, err := loadConfigFromFile(*configFilePath.Get(), lua.GetState())
+		userConfig, err := loadConfigFromFile(*configFilePath.Get(), lua)
 		if err != nil {
 			return err
 		}
 
+		// An unknown theme is reported by Validate, the config is then used without theme
+		_ = userConfig.ApplyTheme(userConfig.General.Theme)
+
 		AppConfig = userConfig
 	} else {
-		AppConfig = getDefaultConfig()
+		AppConfig = GetDefaultConfig()
 	}
 
 	return nil
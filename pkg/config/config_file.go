@@ -7,6 +7,7 @@ import (
 	"github.com/yuin/gluamapper"
 	gopher_lua "github.com/yuin/gopher-lua"
 
+	"github.com/dinhhuy258/fm/pkg/config/lua"
 	"github.com/dinhhuy258/fm/pkg/fs"
 	"github.com/dinhhuy258/fm/pkg/types"
 )
@@ -17,8 +18,8 @@ const AppDir = "fm"
 // ConfigFileName is the name of the config file that gets created.
 const ConfigFileName = "config.lua"
 
-// getConfigFilePath returns the user config file
-func getConfigFilePath() types.Optional[string] {
+// GetConfigFilePath returns the user config file
+func GetConfigFilePath() types.Optional[string] {
 	configDir := os.Getenv("XDG_CONFIG_HOME")
 
 	if configDir == "" {
@@ -40,8 +41,11 @@ func getConfigFilePath() types.Optional[string] {
 }
 
 // loadConfigFromFile loads the config file from the given path.
-func loadConfigFromFile(path string, luaState *gopher_lua.LState) (*Config, error) {
-	defaultConfigTbl := getDefaultConfig().toLuaTable(luaState)
+func loadConfigFromFile(path string, lua *lua.Lua) (*Config, error) {
+	luaState := lua.GetState()
+
+	defaultConfigTbl := GetDefaultConfig().toLuaTable(luaState)
+	lua.RegisterHooks(defaultConfigTbl)
 	luaState.SetGlobal("fm", defaultConfigTbl)
 
 	if err := luaState.DoFile(path); err != nil {
@@ -50,17 +54,20 @@ func loadConfigFromFile(path string, luaState *gopher_lua.LState) (*Config, erro
 
 	var config Config
 
-	mapper := gluamapper.NewMapper(gluamapper.Option{
-		NameFunc: func(s string) string {
-			return s
-		},
-		TagName: "mapper",
-	})
-
 	fmConfig, _ := luaState.GetGlobal("fm").(*gopher_lua.LTable)
-	if err := mapper.Map(fmConfig, &config); err != nil {
+	if err := newMapper().Map(fmConfig, &config); err != nil {
 		return nil, err
 	}
 
 	return &config, nil
 }
+
+// newMapper creates the mapper which maps lua tables to the config structs
+func newMapper() *gluamapper.Mapper {
+	return gluamapper.NewMapper(gluamapper.Option{
+		NameFunc: func(s string) string {
+			return s
+		},
+		TagName: "mapper",
+	})
+}
@@ -1,13 +1,43 @@
 package config
 
-// getDefaultConfig returns the default configuration for the application.
-func getDefaultConfig() *Config {
+import "github.com/dinhhuy258/fm/pkg/types"
+
+// GetDefaultConfig returns the default configuration for the application.
+func GetDefaultConfig() *Config {
 	return &Config{
 		General: &GeneralConfig{
 			FrameUI: &FrameUI{
 				SelFrameColor: "green",
 				FrameColor:    "white",
 			},
+			TitleStyle: &StyleConfig{},
+			InfoStyle: &StyleConfig{
+				Fg: "#626262",
+			},
+			FooterStyle: &StyleConfig{
+				Fg: "#626262",
+			},
+			HeaderFormat: &StatusLineConfig{
+				Segments: []*StatusSegmentConfig{
+					{Format: "Mode: {mode}"},
+					{Format: "Items: {total}"},
+					{Format: "Selected: {selected}"},
+					{Format: "Count: {count}"},
+				},
+				Separator: " | ",
+			},
+			FooterFormat: &StatusLineConfig{
+				Segments: []*StatusSegmentConfig{
+					{Format: "Press ? for help"},
+					{
+						Format: "{unread_errors} unread error(s)",
+						Style: &StyleConfig{
+							Fg: "red",
+						},
+					},
+				},
+				Separator: " | ",
+			},
 			LogInfoUI: &UIConfig{
 				Prefix: "[Info] ",
 				Suffix: "",
@@ -67,25 +97,61 @@ func getDefaultConfig() *Config {
 						},
 					},
 				},
+				RangeUI: &UIConfig{
+					Prefix: " (",
+					Suffix: ")",
+					Style: &StyleConfig{
+						Fg: "yellow",
+					},
+				},
 				IndexHeader: &ExplorerTableHeaderConfig{
 					Name:       "index",
 					Percentage: 15,
+					MinWidth:   5,
+					Priority:   1,
 				},
 				NameHeader: &ExplorerTableHeaderConfig{
 					Name:       "┌──── name",
 					Percentage: 85,
+					MinWidth:   30,
+				},
+				UsageHeader: &ExplorerTableHeaderConfig{
+					Name:       "usage",
+					Percentage: 45,
+					MinWidth:   24,
+					Priority:   2,
 				},
 				FirstEntryPrefix: "├─",
 				EntryPrefix:      "├─",
 				LastEntryPrefix:  "└─",
 			},
+			Layout: &LayoutConfig{
+				Border:              true,
+				BorderMinWidth:      40,
+				BorderMinHeight:     12,
+				CompactHeaderHeight: 16,
+			},
+			Preview: &PreviewConfig{
+				Visible:         false,
+				WidthPercentage: 50,
+				ImageProtocol:   string(types.ImageProtocolAuto),
+				MaxImageSize:    20 * 1024 * 1024,
+			},
+			DirectorySize: &DirectorySizeConfig{
+				Enabled:     false,
+				Concurrency: 4,
+			},
 			Sorting: &SortingConfig{
 				Reverse:          newBool(false),
 				SortType:         "dirFirst",
 				IgnoreCase:       newBool(true),
 				IgnoreDiacritics: newBool(true),
 			},
-			ShowHidden: false,
+			ShowHidden:         false,
+			WatchConfig:        false,
+			KeySequenceTimeout: 1000,
+			WhichKeyDelay:      300,
+			HistorySize:        1000,
 		},
 		NodeTypes: &NodeTypesConfig{
 			File: &NodeTypeConfig{
@@ -112,10 +178,18 @@ func getDefaultConfig() *Config {
 				},
 				Icon: "",
 			},
+			BrokenSymlink: &NodeTypeConfig{
+				Style: &StyleConfig{
+					Fg: "red",
+				},
+				Icon: "",
+			},
 			Extensions: getExtensionsNodeTypeConfig(),
 			Specials:   getSpecialsNodeTypeConfig(),
 		},
+		Themes: map[string]*ThemeConfig{},
 		Modes: &ModesConfig{
+			Global:   globalKeyBindings,
 			Builtins: builtinModeConfigs,
 			Customs:  map[string]*ModeConfig{},
 		},
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	gopher_lua "github.com/yuin/gopher-lua"
+)
+
+// dumpIndent is the indentation used for nested tables
+const dumpIndent = "  "
+
+// luaIdentifierRegex matches the table keys which can be written without brackets
+var luaIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// luaKeywords can not be used as table keys without brackets
+var luaKeywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true, "end": true,
+	"false": true, "for": true, "function": true, "goto": true, "if": true, "in": true,
+	"local": true, "nil": true, "not": true, "or": true, "repeat": true, "return": true,
+	"then": true, "true": true, "until": true, "while": true,
+}
+
+// DumpConfig writes the given config as lua source which can be loaded back as a config file.
+// The values changed by the theme are written as they were before it was applied, the theme is
+// applied again when the config is loaded. Table keys are sorted so the output is stable.
+func DumpConfig(w io.Writer, cfg *Config) error {
+	luaState := gopher_lua.NewState()
+	defer luaState.Close()
+
+	configTbl := cfg.withoutTheme().toLuaTable(luaState)
+
+	writer := bufio.NewWriter(w)
+	writer.WriteString("local fm = fm\n")
+
+	for _, key := range sortedTableKeys(configTbl) {
+		writer.WriteString("\nfm." + key + " = ")
+		writeLuaValue(writer, configTbl.RawGetString(key), 0)
+		writer.WriteString("\n")
+	}
+
+	return writer.Flush()
+}
+
+// writeLuaValue writes the lua source of the given value
+func writeLuaValue(writer *bufio.Writer, value gopher_lua.LValue, depth int) {
+	switch v := value.(type) {
+	case gopher_lua.LString:
+		writer.WriteString(luaString(string(v)))
+	case gopher_lua.LNumber:
+		writer.WriteString(v.String())
+	case gopher_lua.LBool:
+		writer.WriteString(strconv.FormatBool(bool(v)))
+	case *gopher_lua.LTable:
+		writeLuaTable(writer, v, depth)
+	default:
+		writer.WriteString("nil")
+	}
+}
+
+// writeLuaTable writes the lua source of the given table, arrays keep their order,
+// the fields of other tables are sorted by key
+func writeLuaTable(writer *bufio.Writer, tbl *gopher_lua.LTable, depth int) {
+	indent := strings.Repeat(dumpIndent, depth+1)
+
+	if length := tbl.MaxN(); length > 0 {
+		writer.WriteString("{\n")
+
+		for i := 1; i <= length; i++ {
+			writer.WriteString(indent)
+			writeLuaValue(writer, tbl.RawGetInt(i), depth+1)
+			writer.WriteString(",\n")
+		}
+
+		writer.WriteString(strings.Repeat(dumpIndent, depth) + "}")
+
+		return
+	}
+
+	keys := sortedTableKeys(tbl)
+	if len(keys) == 0 {
+		writer.WriteString("{}")
+
+		return
+	}
+
+	writer.WriteString("{\n")
+
+	for _, key := range keys {
+		value := tbl.RawGetString(key)
+		if value.Type() == gopher_lua.LTFunction {
+			writer.WriteString(indent + "-- " + key + ": lua functions can not be dumped\n")
+
+			continue
+		}
+
+		writer.WriteString(indent + luaKey(key) + " = ")
+		writeLuaValue(writer, value, depth+1)
+		writer.WriteString(",\n")
+	}
+
+	writer.WriteString(strings.Repeat(dumpIndent, depth) + "}")
+}
+
+// sortedTableKeys returns the string keys of the table in sorted order
+func sortedTableKeys(tbl *gopher_lua.LTable) []string {
+	var keys []string
+
+	tbl.ForEach(func(key, _ gopher_lua.LValue) {
+		if keyStr, ok := key.(gopher_lua.LString); ok {
+			keys = append(keys, string(keyStr))
+		}
+	})
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// luaKey formats a table key, keys which are not identifiers are wrapped in brackets
+func luaKey(key string) string {
+	if luaIdentifierRegex.MatchString(key) && !luaKeywords[key] {
+		return key
+	}
+
+	return "[" + luaString(key) + "]"
+}
+
+// luaString formats a string literal, multi-line strings (e.g. bash scripts) are written as
+// long brackets to keep them readable
+func luaString(s string) string {
+	if strings.Contains(s, "\n") {
+		// A value ending with "]" followed by the level would close the bracket early
+		level := "="
+		for strings.Contains(s+"]", "]"+level+"]") {
+			level += "="
+		}
+
+		// The newline right after the opening long bracket is skipped by lua
+		return "[" + level + "[\n" + s + "]" + level + "]"
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '"' || c == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(c)
+		case c < ' ' || c == 0x7f:
+			// Always three digits, lua reads up to three digits after the backslash
+			fmt.Fprintf(&builder, "\\%03d", c)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	builder.WriteByte('"')
+
+	return builder.String()
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	gopher_lua "github.com/yuin/gopher-lua"
+
+	"github.com/dinhhuy258/fm/pkg/config/lua"
+)
+
+// Events which can be subscribed from the config file with fm.on(event, callback)
+const (
+	// EventStart is fired once when fm starts
+	EventStart = "start"
+	// EventDirectoryChanged is fired after the current directory changed
+	EventDirectoryChanged = "directory_changed"
+	// EventFocusChanged is fired after the focused entry changed
+	EventFocusChanged = "focus_changed"
+	// EventQuit is fired right before fm quits
+	EventQuit = "quit"
+)
+
+// HookEvents contains all supported hook events
+var HookEvents = []string{
+	EventStart,
+	EventDirectoryChanged,
+	EventFocusChanged,
+	EventQuit,
+}
+
+// RunHooks calls the callbacks registered for the given event and returns the messages
+// they want to send back to fm.
+// A callback can return either a single message ({ name = "...", args = { ... } })
+// or a list of messages.
+func RunHooks(lua *lua.Lua, event string, data map[string]string) ([]*MessageConfig, error) {
+	results, err := lua.CallHooks(event, data)
+
+	var messages []*MessageConfig
+
+	for _, result := range results {
+		resultMessages, mapErr := toMessageConfigs(result)
+		if mapErr != nil {
+			return messages, fmt.Errorf("invalid value returned from %s hook: %w", event, mapErr)
+		}
+
+		messages = append(messages, resultMessages...)
+	}
+
+	if err != nil {
+		return messages, fmt.Errorf("%s hook failed: %w", event, err)
+	}
+
+	return messages, nil
+}
+
+// toMessageConfigs maps the value returned from a hook to message configs
+func toMessageConfigs(value gopher_lua.LValue) ([]*MessageConfig, error) {
+	tbl, ok := value.(*gopher_lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("expected a table, got %s", value.Type().String())
+	}
+
+	// Single message
+	if tbl.RawGetString("name") != gopher_lua.LNil {
+		message, err := toMessageConfig(tbl)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*MessageConfig{message}, nil
+	}
+
+	// List of messages
+	messages := make([]*MessageConfig, 0, tbl.Len())
+	for i := 1; i <= tbl.Len(); i++ {
+		messageTbl, ok := tbl.RawGetInt(i).(*gopher_lua.LTable)
+		if !ok {
+			return nil, fmt.Errorf("message #%d is not a table", i)
+		}
+
+		message, err := toMessageConfig(messageTbl)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// toMessageConfig maps a lua table to a message config
+func toMessageConfig(tbl *gopher_lua.LTable) (*MessageConfig, error) {
+	var message MessageConfig
+	if err := newMapper().Map(tbl, &message); err != nil {
+		return nil, err
+	}
+
+	if message.Name == "" {
+		return nil, errors.New("message name is missing")
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	gopher_lua "github.com/yuin/gopher-lua"
+
+	"github.com/dinhhuy258/fm/pkg/config/lua"
+)
+
+const (
+	// CompletionPath completes file paths relative to the current directory
+	CompletionPath = "path"
+	// CompletionMessage completes fm message names and their path arguments
+	CompletionMessage = "message"
+
+	// PreviewMatchCount previews the number of entries whose name matches the input pattern
+	PreviewMatchCount = "match_count"
+)
+
+// InputValidator checks an input value, it returns an empty string if the value is valid,
+// otherwise the reason why the value is invalid
+type InputValidator func(value string) string
+
+// NewInputValidator creates the validator of the input config, a nil validator means
+// every value is valid.
+// validate can be a regex which the value must match or a lua function called with the value.
+// The function returns true (or nothing) for a valid value, false or an error message otherwise.
+func NewInputValidator(ic *InputConfig, luaEngine *lua.Lua) (InputValidator, error) {
+	if ic == nil || ic.Validate == nil {
+		return nil, nil
+	}
+
+	switch validate := ic.Validate.(type) {
+	case string:
+		if validate == "" {
+			return nil, nil
+		}
+
+		regex, err := regexp.Compile(validate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", validate, err)
+		}
+
+		return func(value string) string {
+			if regex.MatchString(value) {
+				return ""
+			}
+
+			return "must match " + validate
+		}, nil
+	case *gopher_lua.LFunction:
+		return func(value string) string {
+			result, err := luaEngine.CallFunction(validate, value)
+			if err != nil {
+				return err.Error()
+			}
+
+			switch result := result.(type) {
+			case gopher_lua.LBool:
+				if !result {
+					return "invalid value"
+				}
+			case gopher_lua.LString:
+				return string(result)
+			}
+
+			return ""
+		}, nil
+	}
+
+	return nil, fmt.Errorf("validate must be a regex or a function, got %T", ic.Validate)
+}
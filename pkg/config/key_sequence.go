@@ -0,0 +1,37 @@
+package config
+
+import "strings"
+
+// spaceKeyName can be used for the space key inside a key sequence, e.g. "space f"
+const spaceKeyName = "space"
+
+// ParseKeySequence splits a key binding into the keys of its sequence, e.g. "g g" -> ["g", "g"].
+// A binding of a single space is the space key itself.
+func ParseKeySequence(binding string) []string {
+	if binding == " " {
+		return []string{" "}
+	}
+
+	keys := strings.Fields(binding)
+	for i, key := range keys {
+		if key == spaceKeyName {
+			keys[i] = " "
+		}
+	}
+
+	return keys
+}
+
+// FormatKeySequence formats the keys of a sequence for display, e.g. ["g", " "] -> "g space"
+func FormatKeySequence(keys []string) string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		if key == " " {
+			key = spaceKeyName
+		}
+
+		names[i] = key
+	}
+
+	return strings.Join(names, " ")
+}
@@ -1,18 +1,23 @@
 package lua
 
 import (
+	"errors"
+	"sort"
+
 	lua "github.com/yuin/gopher-lua"
 )
 
 // Lua represent object stores lua state
 type Lua struct {
 	state *lua.LState
+	hooks map[string][]*lua.LFunction
 }
 
 // NewLua create new Lua object instance
 func NewLua() *Lua {
 	return &Lua{
 		state: lua.NewState(),
+		hooks: make(map[string][]*lua.LFunction),
 	}
 }
 
@@ -21,6 +26,110 @@ func (l *Lua) GetState() *lua.LState {
 	return l.state
 }
 
+// RegisterHooks exposes the `on` function on the given table,
+// config files use it to subscribe to events: fm.on("event", function(ev) ... end)
+func (l *Lua) RegisterHooks(tbl *lua.LTable) {
+	tbl.RawSetString("on", l.state.NewFunction(l.on))
+}
+
+// on is the lua binding which registers a callback for an event
+func (l *Lua) on(luaState *lua.LState) int {
+	event := luaState.CheckString(1)
+	callback := luaState.CheckFunction(2)
+
+	l.hooks[event] = append(l.hooks[event], callback)
+
+	return 0
+}
+
+// GetHookEvents returns the sorted names of the events which have at least one callback
+func (l *Lua) GetHookEvents() []string {
+	events := make([]string, 0, len(l.hooks))
+	for event := range l.hooks {
+		events = append(events, event)
+	}
+
+	sort.Strings(events)
+
+	return events
+}
+
+// CallHooks calls every callback registered for the given event in registration order.
+// The event data is passed to the callbacks as a table, the values returned by
+// the callbacks are collected and returned.
+func (l *Lua) CallHooks(event string, data map[string]string) ([]lua.LValue, error) {
+	callbacks := l.hooks[event]
+	if len(callbacks) == 0 {
+		return nil, nil
+	}
+
+	results := make([]lua.LValue, 0, len(callbacks))
+
+	for _, callback := range callbacks {
+		eventTbl := l.state.NewTable()
+		eventTbl.RawSetString("event", lua.LString(event))
+		for key, value := range data {
+			eventTbl.RawSetString(key, lua.LString(value))
+		}
+
+		if err := l.state.CallByParam(lua.P{
+			Fn:      callback,
+			NRet:    1,
+			Protect: true,
+		}, eventTbl); err != nil {
+			return results, toError(err)
+		}
+
+		result := l.state.Get(-1)
+		l.state.Pop(1)
+
+		if result != lua.LNil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// CallFunction calls a lua function with string arguments and returns its first result
+func (l *Lua) CallFunction(fn *lua.LFunction, args ...string) (lua.LValue, error) {
+	luaArgs := make([]lua.LValue, len(args))
+	for i, arg := range args {
+		luaArgs[i] = lua.LString(arg)
+	}
+
+	if err := l.state.CallByParam(lua.P{
+		Fn:      fn,
+		NRet:    1,
+		Protect: true,
+	}, luaArgs...); err != nil {
+		return lua.LNil, toError(err)
+	}
+
+	result := l.state.Get(-1)
+	l.state.Pop(1)
+
+	return result, nil
+}
+
+// toError strips the stack trace from lua api errors
+func toError(err error) error {
+	var apiError *lua.ApiError
+	if errors.As(err, &apiError) && apiError.Object != nil {
+		return errors.New(apiError.Object.String())
+	}
+
+	return err
+}
+
+// Replace closes the current lua state and takes over the state and hooks of the given object
+func (l *Lua) Replace(other *Lua) {
+	l.state.Close()
+
+	l.state = other.state
+	l.hooks = other.hooks
+}
+
 // Close lua object state
 func (l *Lua) Close() {
 	l.state.Close()
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetMode returns the config of the mode with the given name, custom modes take precedence
+// over builtin modes
+func (m *ModesConfig) GetMode(name string) *ModeConfig {
+	if modeConfig, exists := m.Customs[name]; exists {
+		return modeConfig
+	}
+
+	if modeConfig, exists := m.Builtins[name]; exists {
+		return modeConfig
+	}
+
+	return nil
+}
+
+// ResolveKeyBindings returns the key bindings of the mode merged with the modes it extends and
+// the global key bindings. Bindings of a mode override the bindings it inherits.
+// When the inheritance chain is broken, the bindings resolved so far are returned with an error.
+func (m *ModesConfig) ResolveKeyBindings(name string) (*KeyBindingsConfig, error) {
+	chain, err := m.getInheritanceChain(name)
+
+	keyBindings := &KeyBindingsConfig{
+		OnKeys:  make(map[string]*ActionConfig, len(m.Global)),
+		OnMouse: make(map[string]*ActionConfig),
+	}
+
+	for key, action := range m.Global {
+		keyBindings.OnKeys[key] = action
+	}
+
+	// Apply the bindings from the root mode down to the mode itself
+	for i := len(chain) - 1; i >= 0; i-- {
+		modeKeyBindings := chain[i].KeyBindings
+
+		for key, action := range modeKeyBindings.OnKeys {
+			keyBindings.OnKeys[key] = action
+		}
+
+		for event, action := range modeKeyBindings.OnMouse {
+			keyBindings.OnMouse[event] = action
+		}
+
+		if modeKeyBindings.OnNumber != nil {
+			keyBindings.OnNumber = modeKeyBindings.OnNumber
+		}
+
+		if modeKeyBindings.Default != nil {
+			keyBindings.Default = modeKeyBindings.Default
+		}
+	}
+
+	return keyBindings, err
+}
+
+// ResolveInput returns the input config of the mode, or of the closest mode it extends which has
+// one. Nil means the mode has no input.
+func (m *ModesConfig) ResolveInput(name string) *InputConfig {
+	chain, _ := m.getInheritanceChain(name)
+
+	for _, modeConfig := range chain {
+		if modeConfig.Input != nil {
+			return modeConfig.Input
+		}
+	}
+
+	return nil
+}
+
+// getInheritanceChain returns the mode followed by the modes it extends
+func (m *ModesConfig) getInheritanceChain(name string) ([]*ModeConfig, error) {
+	var chain []*ModeConfig
+
+	var names []string
+	visited := map[string]bool{}
+
+	for name != "" {
+		if visited[name] {
+			cycle := strings.Join(append(names, name), " -> ")
+
+			return chain, fmt.Errorf("mode inheritance cycle: %s", cycle)
+		}
+
+		modeConfig := m.GetMode(name)
+		if modeConfig == nil {
+			if len(names) == 0 {
+				return chain, fmt.Errorf("unknown mode %q", name)
+			}
+
+			return chain, fmt.Errorf("mode %q extends unknown mode %q", names[len(names)-1], name)
+		}
+
+		visited[name] = true
+		names = append(names, name)
+		chain = append(chain, modeConfig)
+		name = modeConfig.Extends
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+// Mouse events which can be bound in on_mouse.
+const (
+	// MouseEventClick is a left click on an entry
+	MouseEventClick = "click"
+	// MouseEventDoubleClick is a second left click on the same entry within a short delay
+	MouseEventDoubleClick = "double_click"
+	// MouseEventWheelUp is the mouse wheel scrolled up over the explorer table
+	MouseEventWheelUp = "wheel_up"
+	// MouseEventWheelDown is the mouse wheel scrolled down over the explorer table
+	MouseEventWheelDown = "wheel_down"
+	// MouseEventHeaderClick is a left click on a column header of the explorer table
+	MouseEventHeaderClick = "header_click"
+	// MouseEventPathClick is a left click on the current path in the header
+	MouseEventPathClick = "path_click"
+)
+
+// MouseEvents lists the mouse events which can be bound in on_mouse
+var MouseEvents = []string{
+	MouseEventClick,
+	MouseEventDoubleClick,
+	MouseEventWheelUp,
+	MouseEventWheelDown,
+	MouseEventHeaderClick,
+	MouseEventPathClick,
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"regexp"
+
+	gopher_lua "github.com/yuin/gopher-lua"
+)
+
+// Placeholders which can be used in the segments of header_format and footer_format.
+const (
+	// PlaceholderPwd is the current directory
+	PlaceholderPwd = "pwd"
+	// PlaceholderMode is the current mode
+	PlaceholderMode = "mode"
+	// PlaceholderTotal is the number of entries of the current directory
+	PlaceholderTotal = "total"
+	// PlaceholderSelected is the number of selected paths, empty if nothing is selected
+	PlaceholderSelected = "selected"
+	// PlaceholderSelectedSize is the total size of the selected paths, empty if nothing is selected
+	PlaceholderSelectedSize = "selected_size"
+	// PlaceholderSort is the sort type, followed by "reverse" if the order is reversed
+	PlaceholderSort = "sort"
+	// PlaceholderHidden is "hidden" if hidden files are shown, empty otherwise
+	PlaceholderHidden = "hidden"
+	// PlaceholderFreeSpace is the free space of the file system of the current directory
+	PlaceholderFreeSpace = "free_space"
+	// PlaceholderGitBranch is the git branch of the current directory, empty outside a repository
+	PlaceholderGitBranch = "git_branch"
+	// PlaceholderFocusName is the name of the focused entry
+	PlaceholderFocusName = "focus_name"
+	// PlaceholderCount is the pending count, empty if there is no count
+	PlaceholderCount = "count"
+	// PlaceholderUnreadErrors is the number of unread errors, empty if there is none
+	PlaceholderUnreadErrors = "unread_errors"
+)
+
+// StatusPlaceholders lists the placeholders which can be used in a status line segment
+var StatusPlaceholders = []string{
+	PlaceholderPwd,
+	PlaceholderMode,
+	PlaceholderTotal,
+	PlaceholderSelected,
+	PlaceholderSelectedSize,
+	PlaceholderSort,
+	PlaceholderHidden,
+	PlaceholderFreeSpace,
+	PlaceholderGitBranch,
+	PlaceholderFocusName,
+	PlaceholderCount,
+	PlaceholderUnreadErrors,
+}
+
+// placeholderRegexp matches a placeholder of a segment format, e.g. {mode}
+var placeholderRegexp = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// StatusSegmentConfig represents a part of a status line. The segment is hidden when one of the
+// placeholders of its format is empty.
+type StatusSegmentConfig struct {
+	Format string `mapper:"format"`
+	// Style is the style of the segment, the style of the status line is used if it is not set
+	Style *StyleConfig `mapper:"style"`
+}
+
+// toLuaTable convert to LuaTable object
+func (ssc *StatusSegmentConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	tbl.RawSetString("format", gopher_lua.LString(ssc.Format))
+
+	if ssc.Style != nil {
+		tbl.RawSetString("style", ssc.Style.toLuaTable(luaState))
+	}
+
+	return tbl
+}
+
+// StatusLineConfig represents the config of the header or the footer
+type StatusLineConfig struct {
+	Segments  []*StatusSegmentConfig `mapper:"segments"`
+	Separator string                 `mapper:"separator"`
+}
+
+// toLuaTable convert to LuaTable object
+func (slc *StatusLineConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	segmentsTbl := luaState.NewTable()
+	for _, segment := range slc.Segments {
+		segmentsTbl.Append(segment.toLuaTable(luaState))
+	}
+
+	tbl.RawSetString("segments", segmentsTbl)
+	tbl.RawSetString("separator", gopher_lua.LString(slc.Separator))
+
+	return tbl
+}
+
+// ExpandPlaceholders replaces the placeholders of the format with the values returned by
+// getValue. It returns false if one of the placeholders is empty.
+func ExpandPlaceholders(format string, getValue func(placeholder string) string) (string, bool) {
+	complete := true
+
+	result := placeholderRegexp.ReplaceAllStringFunc(format, func(match string) string {
+		value := getValue(match[1 : len(match)-1])
+		if value == "" {
+			complete = false
+		}
+
+		return value
+	})
+
+	return result, complete
+}
+
+// getPlaceholders returns the placeholders used in the format
+func getPlaceholders(format string) []string {
+	matches := placeholderRegexp.FindAllStringSubmatch(format, -1)
+
+	placeholders := make([]string, 0, len(matches))
+	for _, match := range matches {
+		placeholders = append(placeholders, match[1])
+	}
+
+	return placeholders
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+const (
+	// Hex color validation constants
+	shortHexLength = 4 // #RGB
+	longHexLength  = 7 // #RRGGBB
+	hexPrefix      = "#"
+)
+
+// Supported style decorations
+const (
+	DecorationBold       = "bold"
+	DecorationItalic     = "italic"
+	DecorationUnderline  = "underline"
+	DecorationUnderscore = "underscore"
+	DecorationReverse    = "reverse"
+)
+
+// ColorEntry represents a color with both foreground and background variants
+type ColorEntry struct {
+	Foreground string
+	Background string
+}
+
+// ensureHexPrefix adds # prefix to hex color if not present
+func ensureHexPrefix(hexColor string) string {
+	if strings.HasPrefix(hexColor, hexPrefix) {
+		return hexPrefix + hexColor
+	}
+
+	return hexPrefix + hexColor
+}
+
+// colorMap maps color names to their hex values
+var colorMap = map[string]ColorEntry{
+	"default": {ensureHexPrefix(color.FgWhite.RGB().Hex()), ensureHexPrefix(color.BgBlack.RGB().Hex())},
+	"black":   {ensureHexPrefix(color.FgBlack.RGB().Hex()), ensureHexPrefix(color.BgBlack.RGB().Hex())},
+	"red":     {ensureHexPrefix(color.FgRed.RGB().Hex()), ensureHexPrefix(color.BgRed.RGB().Hex())},
+	"green":   {ensureHexPrefix(color.FgGreen.RGB().Hex()), ensureHexPrefix(color.BgGreen.RGB().Hex())},
+	"yellow":  {ensureHexPrefix(color.FgYellow.RGB().Hex()), ensureHexPrefix(color.BgYellow.RGB().Hex())},
+	"blue":    {ensureHexPrefix(color.FgBlue.RGB().Hex()), ensureHexPrefix(color.BgBlue.RGB().Hex())},
+	"magenta": {ensureHexPrefix(color.FgMagenta.RGB().Hex()), ensureHexPrefix(color.BgMagenta.RGB().Hex())},
+	"cyan":    {ensureHexPrefix(color.FgCyan.RGB().Hex()), ensureHexPrefix(color.BgCyan.RGB().Hex())},
+	"white":   {ensureHexPrefix(color.FgWhite.RGB().Hex()), ensureHexPrefix(color.BgWhite.RGB().Hex())},
+}
+
+// isValidHexValue validates if a string is a valid hex color value.
+func isValidHexValue(hex string) bool {
+	if hex == "" {
+		return false
+	}
+
+	if len(hex) != shortHexLength && len(hex) != longHexLength {
+		return false
+	}
+
+	if !strings.HasPrefix(hex, hexPrefix) {
+		return false
+	}
+
+	// Validate hex characters
+	for _, char := range hex[1:] {
+		if !isHexChar(char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isHexChar checks if a character is a valid hexadecimal digit
+func isHexChar(char rune) bool {
+	return (char >= '0' && char <= '9') ||
+		(char >= 'A' && char <= 'F') ||
+		(char >= 'a' && char <= 'f')
+}
+
+// IsValidColor checks if the given string is a hex color or a known color name.
+func IsValidColor(colorStr string) bool {
+	if isValidHexValue(colorStr) {
+		return true
+	}
+
+	_, exists := colorMap[strings.ToLower(colorStr)]
+
+	return exists
+}
+
+// IsValidDecoration checks if the given string is a supported style decoration.
+func IsValidDecoration(decoration string) bool {
+	switch strings.ToLower(decoration) {
+	case DecorationBold, DecorationItalic, DecorationUnderline, DecorationUnderscore, DecorationReverse:
+		return true
+	}
+
+	return false
+}
+
+// ParseColor converts a color string to a valid hex color.
+func ParseColor(colorStr string, isBg bool) string {
+	if colorStr == "" {
+		return getDefaultColor(isBg)
+	}
+
+	// Check if it's a valid hex color
+	if isValidHexValue(colorStr) {
+		return colorStr
+	}
+
+	// Check if it's a named color
+	if colorEntry, exists := colorMap[strings.ToLower(colorStr)]; exists {
+		if isBg {
+			return colorEntry.Background
+		}
+
+		return colorEntry.Foreground
+	}
+
+	// Fall back to default color
+	return getDefaultColor(isBg)
+}
+
+// getDefaultColor returns the default foreground or background color
+func getDefaultColor(isBg bool) string {
+	if isBg {
+		return colorMap["default"].Background
+	}
+
+	return colorMap["default"].Foreground
+}
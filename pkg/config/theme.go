@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+
+	gopher_lua "github.com/yuin/gopher-lua"
+)
+
+// ThemeConfig represents a named theme. Every part set in a theme replaces the same part of the
+// general config and of the node types, the parts which are not set are kept.
+type ThemeConfig struct {
+	FrameUI *FrameUI `mapper:"frame_ui"`
+
+	TitleStyle  *StyleConfig `mapper:"title_style"`
+	InfoStyle   *StyleConfig `mapper:"info_style"`
+	FooterStyle *StyleConfig `mapper:"footer_style"`
+
+	LogInfoUI    *UIConfig `mapper:"log_info_ui"`
+	LogWarningUI *UIConfig `mapper:"log_warning_ui"`
+	LogErrorUI   *UIConfig `mapper:"log_error_ui"`
+
+	ExplorerTable *ExplorerTableConfig `mapper:"explorer_table"`
+	NodeTypes     *NodeTypesConfig     `mapper:"node_types"`
+}
+
+// toLuaTable convert to LuaTable object
+func (tc *ThemeConfig) toLuaTable(luaState *gopher_lua.LState) *gopher_lua.LTable {
+	tbl := luaState.NewTable()
+
+	if tc.FrameUI != nil {
+		tbl.RawSetString("frame_ui", tc.FrameUI.toLuaTable(luaState))
+	}
+
+	styles := map[string]*StyleConfig{
+		"title_style":  tc.TitleStyle,
+		"info_style":   tc.InfoStyle,
+		"footer_style": tc.FooterStyle,
+	}
+	for name, style := range styles {
+		if style != nil {
+			tbl.RawSetString(name, style.toLuaTable(luaState))
+		}
+	}
+
+	uis := map[string]*UIConfig{
+		"log_info_ui":    tc.LogInfoUI,
+		"log_warning_ui": tc.LogWarningUI,
+		"log_error_ui":   tc.LogErrorUI,
+	}
+	for name, ui := range uis {
+		if ui != nil {
+			tbl.RawSetString(name, ui.toLuaTable(luaState))
+		}
+	}
+
+	if tc.ExplorerTable != nil {
+		tbl.RawSetString("explorer_table", tc.ExplorerTable.toLuaTable(luaState))
+	}
+
+	if tc.NodeTypes != nil {
+		tbl.RawSetString("node_types", tc.NodeTypes.toLuaTable(luaState))
+	}
+
+	return tbl
+}
+
+// ApplyTheme applies the theme with the given name on top of the config without theme,
+// an empty name removes the current theme
+func (c *Config) ApplyTheme(name string) error {
+	var theme *ThemeConfig
+
+	if name != "" {
+		var exists bool
+		if theme, exists = c.Themes[name]; !exists || theme == nil {
+			return fmt.Errorf("unknown theme %q", name)
+		}
+	}
+
+	// The parts of the config covered by themes are kept to switch between themes
+	if c.baseTheme == nil {
+		c.baseTheme = c.captureTheme()
+	}
+
+	c.restoreTheme(c.baseTheme)
+
+	if theme != nil {
+		c.overlayTheme(theme)
+	}
+
+	c.General.Theme = name
+
+	return nil
+}
+
+// withoutTheme returns a copy of the config with the parts changed by the theme restored, e.g. to
+// dump the config. The name of the theme is kept.
+func (c *Config) withoutTheme() *Config {
+	if c.baseTheme == nil || c.General == nil {
+		return c
+	}
+
+	general := *c.General
+	base := *c
+	base.General = &general
+	base.restoreTheme(c.baseTheme)
+
+	return &base
+}
+
+// captureTheme returns the parts of the config which can be changed by a theme
+func (c *Config) captureTheme() *ThemeConfig {
+	return &ThemeConfig{
+		FrameUI:       c.General.FrameUI,
+		TitleStyle:    c.General.TitleStyle,
+		InfoStyle:     c.General.InfoStyle,
+		FooterStyle:   c.General.FooterStyle,
+		LogInfoUI:     c.General.LogInfoUI,
+		LogWarningUI:  c.General.LogWarningUI,
+		LogErrorUI:    c.General.LogErrorUI,
+		ExplorerTable: c.General.ExplorerTable,
+		NodeTypes:     c.NodeTypes,
+	}
+}
+
+// restoreTheme sets the parts of the config captured by captureTheme.
+// Themes never modify these parts in place, they are replaced by copies.
+func (c *Config) restoreTheme(base *ThemeConfig) {
+	gc := c.General
+
+	gc.FrameUI = base.FrameUI
+	gc.TitleStyle = base.TitleStyle
+	gc.InfoStyle = base.InfoStyle
+	gc.FooterStyle = base.FooterStyle
+	gc.LogInfoUI = base.LogInfoUI
+	gc.LogWarningUI = base.LogWarningUI
+	gc.LogErrorUI = base.LogErrorUI
+	gc.ExplorerTable = base.ExplorerTable
+	c.NodeTypes = base.NodeTypes
+}
+
+// overlayTheme replaces the parts of the config which are set in the theme
+func (c *Config) overlayTheme(theme *ThemeConfig) {
+	gc := c.General
+
+	gc.FrameUI = overlay(gc.FrameUI, theme.FrameUI)
+	gc.TitleStyle = overlay(gc.TitleStyle, theme.TitleStyle)
+	gc.InfoStyle = overlay(gc.InfoStyle, theme.InfoStyle)
+	gc.FooterStyle = overlay(gc.FooterStyle, theme.FooterStyle)
+	gc.LogInfoUI = overlay(gc.LogInfoUI, theme.LogInfoUI)
+	gc.LogWarningUI = overlay(gc.LogWarningUI, theme.LogWarningUI)
+	gc.LogErrorUI = overlay(gc.LogErrorUI, theme.LogErrorUI)
+
+	if theme.ExplorerTable != nil {
+		gc.ExplorerTable = overlayExplorerTable(gc.ExplorerTable, theme.ExplorerTable)
+	}
+
+	if theme.NodeTypes != nil {
+		c.NodeTypes = overlayNodeTypes(c.NodeTypes, theme.NodeTypes)
+	}
+}
+
+// overlayExplorerTable returns a copy of the explorer table with the styles of the theme.
+// The names and the percentages of the columns are not changed by themes.
+func overlayExplorerTable(
+	etc *ExplorerTableConfig,
+	theme *ExplorerTableConfig,
+) *ExplorerTableConfig {
+	result := *etc
+
+	result.IndexHeader = overlayHeaderStyle(etc.IndexHeader, theme.IndexHeader)
+	result.NameHeader = overlayHeaderStyle(etc.NameHeader, theme.NameHeader)
+	result.SizeHeader = overlayHeaderStyle(etc.SizeHeader, theme.SizeHeader)
+	result.DateHeader = overlayHeaderStyle(etc.DateHeader, theme.DateHeader)
+	result.UsageHeader = overlayHeaderStyle(etc.UsageHeader, theme.UsageHeader)
+	result.DefaultUI = overlay(etc.DefaultUI, theme.DefaultUI)
+	result.FocusUI = overlay(etc.FocusUI, theme.FocusUI)
+	result.SelectionUI = overlay(etc.SelectionUI, theme.SelectionUI)
+	result.FocusSelectionUI = overlay(etc.FocusSelectionUI, theme.FocusSelectionUI)
+	result.RangeUI = overlay(etc.RangeUI, theme.RangeUI)
+
+	if theme.FirstEntryPrefix != "" {
+		result.FirstEntryPrefix = theme.FirstEntryPrefix
+	}
+
+	if theme.EntryPrefix != "" {
+		result.EntryPrefix = theme.EntryPrefix
+	}
+
+	if theme.LastEntryPrefix != "" {
+		result.LastEntryPrefix = theme.LastEntryPrefix
+	}
+
+	return &result
+}
+
+// overlayHeaderStyle returns a copy of the column header with the style of the theme
+func overlayHeaderStyle(
+	header *ExplorerTableHeaderConfig,
+	theme *ExplorerTableHeaderConfig,
+) *ExplorerTableHeaderConfig {
+	if header == nil || theme == nil || theme.Style == nil {
+		return header
+	}
+
+	result := *header
+	result.Style = theme.Style
+
+	return &result
+}
+
+// overlayNodeTypes returns a copy of the node types with the icons and styles of the theme,
+// extensions and specials of the theme are added to the existing ones
+func overlayNodeTypes(ntc *NodeTypesConfig, theme *NodeTypesConfig) *NodeTypesConfig {
+	result := *ntc
+
+	result.File = overlay(ntc.File, theme.File)
+	result.Directory = overlay(ntc.Directory, theme.Directory)
+	result.FileSymlink = overlay(ntc.FileSymlink, theme.FileSymlink)
+	result.DirectorySymlink = overlay(ntc.DirectorySymlink, theme.DirectorySymlink)
+	result.BrokenSymlink = overlay(ntc.BrokenSymlink, theme.BrokenSymlink)
+
+	result.Extensions = maps.Clone(ntc.Extensions)
+	if result.Extensions == nil {
+		result.Extensions = make(map[string]*NodeTypeConfig)
+	}
+
+	maps.Copy(result.Extensions, theme.Extensions)
+
+	result.Specials = maps.Clone(ntc.Specials)
+	if result.Specials == nil {
+		result.Specials = make(map[string]*NodeTypeConfig)
+	}
+
+	maps.Copy(result.Specials, theme.Specials)
+
+	return &result
+}
+
+// overlay returns the value of the theme if it is set, the current value otherwise
+func overlay[T any](current *T, theme *T) *T {
+	if theme != nil {
+		return theme
+	}
+
+	return current
+}
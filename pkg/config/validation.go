@@ -0,0 +1,551 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dinhhuy258/fm/pkg/config/lua"
+	"github.com/dinhhuy258/fm/pkg/types"
+)
+
+// totalColumnPercentage is the sum of the percentages of the explorer table columns
+const totalColumnPercentage = 100
+
+// minPreviewPercentage and maxPreviewPercentage bound the share of the width used by the preview
+const (
+	minPreviewPercentage = 10
+	maxPreviewPercentage = 90
+)
+
+// Diagnostic describes a problem found in the config
+type Diagnostic struct {
+	// Path is the location of the problem in the fm table, e.g. fm.general.frame_ui.frame_color
+	Path    string
+	Message string
+}
+
+// String returns the string representation of the diagnostic
+func (d Diagnostic) String() string {
+	return d.Path + ": " + d.Message
+}
+
+// MessageValidator checks a message, it returns an error if the message is invalid
+type MessageValidator func(message *MessageConfig) error
+
+// validator collects diagnostics while walking through the config
+type validator struct {
+	config          *Config
+	validateMessage MessageValidator
+	diagnostics     []Diagnostic
+}
+
+// Validate checks the given config and the registered hooks, it returns the problems found
+func Validate(cfg *Config, luaEngine *lua.Lua, validateMessage MessageValidator) []Diagnostic {
+	v := &validator{
+		config:          cfg,
+		validateMessage: validateMessage,
+	}
+
+	v.validateGeneral("fm.general", cfg.General)
+	v.validateNodeTypes("fm.node_types", cfg.NodeTypes)
+	v.validateModes("fm.modes", cfg.Modes)
+	v.validateThemes("fm.themes", cfg.Themes)
+
+	if luaEngine != nil {
+		v.validateHookEvents(luaEngine.GetHookEvents())
+	}
+
+	return v.diagnostics
+}
+
+// report adds a diagnostic
+func (v *validator) report(path string, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validateGeneral checks the general config
+func (v *validator) validateGeneral(path string, gc *GeneralConfig) {
+	if gc == nil {
+		v.report(path, "missing general config")
+
+		return
+	}
+
+	v.validateFrameUI(path+".frame_ui", gc.FrameUI)
+	v.validateStyle(path+".title_style", gc.TitleStyle)
+	v.validateStyle(path+".info_style", gc.InfoStyle)
+	v.validateStyle(path+".footer_style", gc.FooterStyle)
+	v.validateStatusLine(path+".header_format", gc.HeaderFormat)
+	v.validateStatusLine(path+".footer_format", gc.FooterFormat)
+
+	v.validateUI(path+".log_info_ui", gc.LogInfoUI)
+	v.validateUI(path+".log_warning_ui", gc.LogWarningUI)
+	v.validateUI(path+".log_error_ui", gc.LogErrorUI)
+
+	v.validateExplorerTable(path+".explorer_table", gc.ExplorerTable)
+
+	if gc.Layout != nil {
+		layoutSizes := map[string]int{
+			"border_min_width":      gc.Layout.BorderMinWidth,
+			"border_min_height":     gc.Layout.BorderMinHeight,
+			"compact_header_height": gc.Layout.CompactHeaderHeight,
+		}
+		for _, name := range sortedKeys(layoutSizes) {
+			if layoutSizes[name] < 0 {
+				v.report(path+".layout."+name, "must not be negative, got %d", layoutSizes[name])
+			}
+		}
+	}
+
+	v.validatePreview(path+".preview", gc.Preview)
+
+	if gc.DirectorySize != nil && gc.DirectorySize.Concurrency <= 0 {
+		v.report(path+".directory_size.concurrency", "must be positive, got %d",
+			gc.DirectorySize.Concurrency)
+	}
+
+	if gc.Sorting != nil {
+		v.validateSortType(path+".sorting.sort_type", gc.Sorting.SortType)
+	}
+
+	if gc.KeySequenceTimeout <= 0 {
+		v.report(path+".key_sequence_timeout", "must be positive, got %d", gc.KeySequenceTimeout)
+	}
+
+	if gc.WhichKeyDelay < 0 {
+		v.report(path+".which_key_delay", "must not be negative, got %d", gc.WhichKeyDelay)
+	}
+
+	if gc.HistorySize < 0 {
+		v.report(path+".history_size", "must not be negative, got %d", gc.HistorySize)
+	}
+
+	if gc.Theme != "" && v.config.Themes[gc.Theme] == nil {
+		v.report(path+".theme", "unknown theme %q", gc.Theme)
+	}
+}
+
+// validateFrameUI checks the frame colors
+func (v *validator) validateFrameUI(path string, fu *FrameUI) {
+	if fu == nil {
+		return
+	}
+
+	v.validateColor(path+".sel_frame_color", fu.SelFrameColor)
+	v.validateColor(path+".frame_color", fu.FrameColor)
+}
+
+// validateStatusLine checks the placeholders and the styles of the segments of a status line
+func (v *validator) validateStatusLine(path string, slc *StatusLineConfig) {
+	if slc == nil {
+		return
+	}
+
+	for i, segment := range slc.Segments {
+		if segment == nil {
+			continue
+		}
+
+		segmentPath := path + ".segments[" + strconv.Itoa(i+1) + "]"
+
+		for _, placeholder := range getPlaceholders(segment.Format) {
+			if !slices.Contains(StatusPlaceholders, placeholder) {
+				v.report(segmentPath+".format", "unknown placeholder {%s}, expected one of %s",
+					placeholder, strings.Join(StatusPlaceholders, ", "))
+			}
+		}
+
+		v.validateStyle(segmentPath+".style", segment.Style)
+	}
+}
+
+// validateThemes checks the colors and decorations of the themes
+func (v *validator) validateThemes(path string, themes map[string]*ThemeConfig) {
+	for _, name := range sortedKeys(themes) {
+		theme := themes[name]
+		if theme == nil {
+			continue
+		}
+
+		themePath := path + luaIndex(name)
+
+		v.validateFrameUI(themePath+".frame_ui", theme.FrameUI)
+		v.validateStyle(themePath+".title_style", theme.TitleStyle)
+		v.validateStyle(themePath+".info_style", theme.InfoStyle)
+		v.validateStyle(themePath+".footer_style", theme.FooterStyle)
+		v.validateUI(themePath+".log_info_ui", theme.LogInfoUI)
+		v.validateUI(themePath+".log_warning_ui", theme.LogWarningUI)
+		v.validateUI(themePath+".log_error_ui", theme.LogErrorUI)
+
+		if etc := theme.ExplorerTable; etc != nil {
+			v.validateExplorerTableStyles(themePath+".explorer_table", etc)
+
+			headers := map[string]*ExplorerTableHeaderConfig{
+				"index_header": etc.IndexHeader,
+				"name_header":  etc.NameHeader,
+				"size_header":  etc.SizeHeader,
+				"date_header":  etc.DateHeader,
+				"usage_header": etc.UsageHeader,
+			}
+			for _, name := range sortedKeys(headers) {
+				if header := headers[name]; header != nil {
+					v.validateStyle(themePath+".explorer_table."+name+".style", header.Style)
+				}
+			}
+		}
+
+		if theme.NodeTypes != nil {
+			v.validateNodeTypes(themePath+".node_types", theme.NodeTypes)
+		}
+	}
+}
+
+// validateExplorerTable checks the explorer table config
+func (v *validator) validateExplorerTable(path string, etc *ExplorerTableConfig) {
+	if etc == nil {
+		v.report(path, "missing explorer table config")
+
+		return
+	}
+
+	v.validateExplorerTableStyles(path, etc)
+
+	// The extra columns are not part of the total of the percentages
+	headers := []struct {
+		name     string
+		header   *ExplorerTableHeaderConfig
+		optional bool
+		extra    bool
+	}{
+		{name: "index_header", header: etc.IndexHeader},
+		{name: "name_header", header: etc.NameHeader},
+		{name: "size_header", header: etc.SizeHeader, optional: true},
+		{name: "date_header", header: etc.DateHeader, optional: true},
+		{name: "usage_header", header: etc.UsageHeader, optional: true, extra: true},
+	}
+
+	totalPercentage := 0
+	for _, h := range headers {
+		if h.header == nil {
+			if !h.optional {
+				v.report(path+"."+h.name, "missing column config")
+			}
+
+			continue
+		}
+
+		if h.header.Percentage < 0 {
+			v.report(path+"."+h.name+".percentage", "must not be negative, got %d", h.header.Percentage)
+		}
+
+		if h.header.MinWidth < 0 {
+			v.report(path+"."+h.name+".min_width", "must not be negative, got %d", h.header.MinWidth)
+		}
+
+		if !h.extra {
+			totalPercentage += h.header.Percentage
+		}
+
+		v.validateStyle(path+"."+h.name+".style", h.header.Style)
+	}
+
+	if totalPercentage != totalColumnPercentage {
+		v.report(path, "column percentages must add up to %d, got %d",
+			totalColumnPercentage, totalPercentage)
+	}
+}
+
+// validateExplorerTableStyles checks the styles of the entries of the explorer table
+func (v *validator) validateExplorerTableStyles(path string, etc *ExplorerTableConfig) {
+	if etc.DefaultUI != nil {
+		v.validateStyle(path+".default_ui.file_style", etc.DefaultUI.FileStyle)
+		v.validateStyle(path+".default_ui.directory_style", etc.DefaultUI.DirectoryStyle)
+	}
+
+	v.validateUI(path+".focus_ui", etc.FocusUI)
+	v.validateUI(path+".selection_ui", etc.SelectionUI)
+	v.validateUI(path+".focus_selection_ui", etc.FocusSelectionUI)
+	v.validateUI(path+".range_ui", etc.RangeUI)
+}
+
+// validateSortType checks that the sort type is supported
+func (v *validator) validateSortType(path string, sortType string) {
+	switch types.SortType(sortType) {
+	case types.SortTypeName, types.SortTypeSize, types.SortTypeDate,
+		types.SortTypeExtension, types.SortTypeDirFirst:
+		return
+	}
+
+	v.report(path, "unknown sort type %q", sortType)
+}
+
+// validatePreview checks the size and the image protocol of the preview
+func (v *validator) validatePreview(path string, pc *PreviewConfig) {
+	if pc == nil {
+		return
+	}
+
+	if pc.WidthPercentage < minPreviewPercentage || pc.WidthPercentage > maxPreviewPercentage {
+		v.report(path+".width_percentage", "must be between %d and %d, got %d",
+			minPreviewPercentage, maxPreviewPercentage, pc.WidthPercentage)
+	}
+
+	switch types.ImageProtocol(pc.ImageProtocol) {
+	case types.ImageProtocolAuto, types.ImageProtocolKitty, types.ImageProtocolHalfBlock,
+		types.ImageProtocolNone:
+	default:
+		v.report(path+".image_protocol", "unknown image protocol %q", pc.ImageProtocol)
+	}
+
+	if pc.MaxImageSize < 0 {
+		v.report(path+".max_image_size", "must not be negative, got %d", pc.MaxImageSize)
+	}
+}
+
+// validateNodeTypes checks the styles of the node types
+func (v *validator) validateNodeTypes(path string, ntc *NodeTypesConfig) {
+	if ntc == nil {
+		v.report(path, "missing node types config")
+
+		return
+	}
+
+	v.validateNodeType(path+".file", ntc.File)
+	v.validateNodeType(path+".directory", ntc.Directory)
+	v.validateNodeType(path+".file_symlink", ntc.FileSymlink)
+	v.validateNodeType(path+".directory_symlink", ntc.DirectorySymlink)
+	v.validateNodeType(path+".broken_symlink", ntc.BrokenSymlink)
+
+	for _, ext := range sortedKeys(ntc.Extensions) {
+		v.validateNodeType(path+".extensions"+luaIndex(ext), ntc.Extensions[ext])
+	}
+
+	for _, fileName := range sortedKeys(ntc.Specials) {
+		v.validateNodeType(path+".specials"+luaIndex(fileName), ntc.Specials[fileName])
+	}
+}
+
+// validateNodeType checks the style of a node type
+func (v *validator) validateNodeType(path string, ntc *NodeTypeConfig) {
+	if ntc == nil {
+		return
+	}
+
+	v.validateStyle(path+".style", ntc.Style)
+}
+
+// validateUI checks the style of an UI config
+func (v *validator) validateUI(path string, ui *UIConfig) {
+	if ui == nil {
+		return
+	}
+
+	v.validateStyle(path+".style", ui.Style)
+}
+
+// validateStyle checks the colors and decorations of a style
+func (v *validator) validateStyle(path string, sc *StyleConfig) {
+	if sc == nil {
+		return
+	}
+
+	v.validateColor(path+".fg", sc.Fg)
+	v.validateColor(path+".bg", sc.Bg)
+
+	for i, decoration := range sc.Decorations {
+		if !IsValidDecoration(decoration) {
+			v.report(path+".decorations["+strconv.Itoa(i+1)+"]", "unknown decoration %q", decoration)
+		}
+	}
+}
+
+// validateColor checks a color string, an empty color means the default color
+func (v *validator) validateColor(path string, color string) {
+	if color == "" || IsValidColor(color) {
+		return
+	}
+
+	v.report(path, "invalid color %q, expected a color name or #RGB/#RRGGBB", color)
+}
+
+// validateModes checks the key bindings of all modes
+func (v *validator) validateModes(path string, modes *ModesConfig) {
+	if modes == nil {
+		v.report(path, "missing modes config")
+
+		return
+	}
+
+	if _, exists := modes.Builtins["default"]; !exists {
+		v.report(path+".builtins", "missing default mode")
+	}
+
+	v.validateOnKeys(path+".global", modes.Global)
+
+	for _, name := range sortedKeys(modes.Builtins) {
+		v.validateMode(path+".builtins"+luaIndex(name), name, modes.Builtins[name])
+	}
+
+	for _, name := range sortedKeys(modes.Customs) {
+		v.validateMode(path+".customs"+luaIndex(name), name, modes.Customs[name])
+	}
+}
+
+// validateMode checks the inheritance and the key bindings of a mode
+func (v *validator) validateMode(path string, name string, mode *ModeConfig) {
+	if mode == nil {
+		return
+	}
+
+	if mode.Extends != "" {
+		if _, err := v.config.Modes.ResolveKeyBindings(name); err != nil {
+			v.report(path+".extends", "%v", err)
+		}
+	}
+
+	v.validateInput(path+".input", mode.Input)
+
+	keyBindingsPath := path + ".key_bindings"
+	keyBindings := mode.KeyBindings
+
+	v.validateOnKeys(keyBindingsPath+".on_keys", keyBindings.OnKeys)
+	v.validateOnMouse(keyBindingsPath+".on_mouse", keyBindings.OnMouse)
+
+	v.validateAction(keyBindingsPath+".on_number", keyBindings.OnNumber)
+	v.validateAction(keyBindingsPath+".default", keyBindings.Default)
+}
+
+// validateInput checks the char limit and the validation of a mode input
+func (v *validator) validateInput(path string, ic *InputConfig) {
+	if ic == nil {
+		return
+	}
+
+	if ic.CharLimit < 0 {
+		v.report(path+".char_limit", "must not be negative, got %d", ic.CharLimit)
+	}
+
+	if _, err := NewInputValidator(ic, nil); err != nil {
+		v.report(path+".validate", "%v", err)
+	}
+
+	switch ic.Completion {
+	case "", CompletionPath, CompletionMessage:
+	default:
+		v.report(path+".completion", "unknown completion %q, expected %q or %q",
+			ic.Completion, CompletionPath, CompletionMessage)
+	}
+
+	if ic.Preview != "" && ic.Preview != PreviewMatchCount {
+		v.report(path+".preview", "unknown preview %q, expected %q", ic.Preview, PreviewMatchCount)
+	}
+}
+
+// validateOnKeys checks the key sequences and the actions of key bindings
+func (v *validator) validateOnKeys(path string, onKeys map[string]*ActionConfig) {
+	for _, key := range sortedKeys(onKeys) {
+		if len(ParseKeySequence(key)) == 0 {
+			v.report(path+luaIndex(key), "empty key sequence")
+		}
+
+		v.validateAction(path+luaIndex(key), onKeys[key])
+	}
+}
+
+// validateOnMouse checks the events and the actions of mouse bindings
+func (v *validator) validateOnMouse(path string, onMouse map[string]*ActionConfig) {
+	for _, event := range sortedKeys(onMouse) {
+		if !slices.Contains(MouseEvents, event) {
+			v.report(path+luaIndex(event), "unknown mouse event %q, expected one of %s",
+				event, strings.Join(MouseEvents, ", "))
+		}
+
+		v.validateAction(path+luaIndex(event), onMouse[event])
+	}
+}
+
+// validateAction checks the messages of an action
+func (v *validator) validateAction(path string, action *ActionConfig) {
+	if action == nil {
+		return
+	}
+
+	for i, message := range action.Messages {
+		v.validateMessageConfig(path+".messages["+strconv.Itoa(i+1)+"]", message)
+	}
+}
+
+// validateMessageConfig checks the name and the arguments of a message
+func (v *validator) validateMessageConfig(path string, message *MessageConfig) {
+	if message == nil {
+		return
+	}
+
+	if v.validateMessage != nil {
+		if err := v.validateMessage(message); err != nil {
+			v.report(path, "%v", err)
+
+			return
+		}
+	}
+
+	if message.Name == "SwitchMode" && len(message.Args) > 0 && !v.modeExists(message.Args[0]) {
+		v.report(path, "SwitchMode to unknown mode %q", message.Args[0])
+	}
+}
+
+// modeExists checks if a mode exists in custom or builtin modes
+func (v *validator) modeExists(name string) bool {
+	if v.config.Modes == nil {
+		return false
+	}
+
+	if _, exists := v.config.Modes.Customs[name]; exists {
+		return true
+	}
+
+	_, exists := v.config.Modes.Builtins[name]
+
+	return exists
+}
+
+// validateHookEvents checks that the hooks are registered for supported events
+func (v *validator) validateHookEvents(events []string) {
+	for _, event := range events {
+		supported := false
+		for _, hookEvent := range HookEvents {
+			if event == hookEvent {
+				supported = true
+
+				break
+			}
+		}
+
+		if !supported {
+			v.report("fm.on", "unknown event %q, supported events are %v", event, HookEvents)
+		}
+	}
+}
+
+// sortedKeys returns the keys of the map in sorted order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// luaIndex formats a table key the way it is written in lua, e.g. ["ctrl+c"]
+func luaIndex(key string) string {
+	return "[" + strconv.Quote(key) + "]"
+}
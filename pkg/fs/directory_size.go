@@ -0,0 +1,110 @@
+package fs
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// directorySizeKey identifies a computed directory size, the size is computed again when the
+// modification time of the directory changes
+type directorySizeKey struct {
+	path    string
+	modTime time.Time
+}
+
+// DirectorySizer computes the recursive size of directories, at most a given number of
+// directories are walked at the same time. The sizes are cached by path and modification time.
+type DirectorySizer struct {
+	semaphore chan struct{}
+
+	mu    sync.Mutex
+	cache map[directorySizeKey]int64
+}
+
+// NewDirectorySizer creates a directory sizer walking at most concurrency directories at the
+// same time
+func NewDirectorySizer(concurrency int) *DirectorySizer {
+	return &DirectorySizer{
+		semaphore: make(chan struct{}, max(concurrency, 1)),
+		cache:     make(map[directorySizeKey]int64),
+	}
+}
+
+// Size returns the sum of the sizes of the files below a directory. Symlinks are not followed
+// and the entries which can't be read are skipped. It waits for a free slot and returns the
+// error of the context if it is cancelled.
+func (s *DirectorySizer) Size(ctx context.Context, path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	key := directorySizeKey{path: path, modTime: info.ModTime()}
+	if size, exists := s.get(key); exists {
+		return size, nil
+	}
+
+	select {
+	case s.semaphore <- struct{}{}:
+		defer func() { <-s.semaphore }()
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	size, err := walkSize(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.cache[key] = size
+	s.mu.Unlock()
+
+	return size, nil
+}
+
+// ClearCache drops the computed sizes, changes below the direct children of a directory don't
+// change its modification time
+func (s *DirectorySizer) ClearCache() {
+	s.mu.Lock()
+	clear(s.cache)
+	s.mu.Unlock()
+}
+
+// get returns the cached size of a directory
+func (s *DirectorySizer) get(key directorySizeKey) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size, exists := s.cache[key]
+
+	return size, exists
+}
+
+// walkSize sums the sizes of the regular files below a directory
+func walkSize(ctx context.Context, path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		// Skip the entries which can't be read
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+
+		if info, err := entry.Info(); err == nil {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
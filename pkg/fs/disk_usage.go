@@ -0,0 +1,165 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// DiskUsage is the disk usage of a directory found by a scan. Only the directories are kept in
+// the tree, the sizes of the files are read from the listing.
+type DiskUsage struct {
+	Path string
+	// Size is the total size of the regular files below the directory, FilesSize the size of the
+	// regular files directly in it
+	Size      int64
+	FilesSize int64
+
+	Parent   *DiskUsage
+	Children map[string]*DiskUsage
+}
+
+// DiskUsageProgress counts the files found by a scan in progress, it can be read while the scan
+// runs
+type DiskUsageProgress struct {
+	files atomic.Int64
+	size  atomic.Int64
+}
+
+// Get returns the number of files found so far and their total size
+func (p *DiskUsageProgress) Get() (int64, int64) {
+	return p.files.Load(), p.size.Load()
+}
+
+// add counts a file
+func (p *DiskUsageProgress) add(size int64) {
+	p.files.Add(1)
+	p.size.Add(size)
+}
+
+// ScanDiskUsage scans the tree under a directory. Symlinks are not followed and the directories
+// which can't be read are counted as empty. It stops with the error of the context when it is
+// cancelled.
+func ScanDiskUsage(
+	ctx context.Context,
+	path string,
+	progress *DiskUsageProgress,
+) (*DiskUsage, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &DiskUsage{Path: path, Children: make(map[string]*DiskUsage)}
+	if err := root.scan(ctx, entries, progress); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// scan adds the sizes of the entries of the directory and of their subdirectories
+func (d *DiskUsage) scan(
+	ctx context.Context,
+	entries []os.DirEntry,
+	progress *DiskUsageProgress,
+) error {
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			child := &DiskUsage{
+				Path:     filepath.Join(d.Path, entry.Name()),
+				Parent:   d,
+				Children: make(map[string]*DiskUsage),
+			}
+
+			// Skip the content of the directories which can't be read
+			if childEntries, err := os.ReadDir(child.Path); err == nil {
+				if err := child.scan(ctx, childEntries, progress); err != nil {
+					return err
+				}
+			}
+
+			d.Children[entry.Name()] = child
+			d.Size += child.Size
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			d.FilesSize += info.Size()
+			d.Size += info.Size()
+			progress.add(info.Size())
+		}
+	}
+
+	return nil
+}
+
+// Find returns the disk usage of a directory below this one, nil if it wasn't scanned
+func (d *DiskUsage) Find(path string) *DiskUsage {
+	relPath, err := filepath.Rel(d.Path, path)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	node := d
+	if relPath == "." {
+		return node
+	}
+
+	for _, name := range strings.Split(relPath, string(filepath.Separator)) {
+		if node = node.Children[name]; node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+// Refresh reads the directory again and updates the sizes of the directory and of its parents:
+// the subdirectories which were removed are dropped and the size of the files is read again.
+// The subdirectories which still exist keep their sizes.
+func (d *DiskUsage) Refresh() error {
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		return err
+	}
+
+	var filesSize int64
+
+	existing := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir():
+			existing[entry.Name()] = true
+		case entry.Type().IsRegular():
+			if info, err := entry.Info(); err == nil {
+				filesSize += info.Size()
+			}
+		}
+	}
+
+	delta := filesSize - d.FilesSize
+	d.FilesSize = filesSize
+
+	for name, child := range d.Children {
+		if !existing[name] {
+			delta -= child.Size
+			delete(d.Children, name)
+		}
+	}
+
+	for node := d; node != nil; node = node.Parent {
+		node.Size += delta
+	}
+
+	return nil
+}
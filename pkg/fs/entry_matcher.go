@@ -0,0 +1,73 @@
+package fs
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Entry types used to match entries by type.
+const (
+	EntryTypeFile    = "file"
+	EntryTypeDir     = "dir"
+	EntryTypeSymlink = "symlink"
+)
+
+// EntryMatcher reports whether an entry matches.
+type EntryMatcher func(entry IEntry) bool
+
+// NewPatternMatcher creates a matcher for the names of the entries.
+// A pattern enclosed in slashes, e.g. /^[0-9]+\.log$/, is a regex, any other pattern is a glob.
+func NewPatternMatcher(pattern string) (EntryMatcher, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		regex, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+
+		return func(entry IEntry) bool {
+			return regex.MatchString(entry.GetName())
+		}, nil
+	}
+
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+
+	return func(entry IEntry) bool {
+		matched, _ := filepath.Match(pattern, entry.GetName())
+
+		return matched
+	}, nil
+}
+
+// NewTypeMatcher creates a matcher for the given entry type.
+// Symlinks only match the symlink type.
+func NewTypeMatcher(entryType string) (EntryMatcher, error) {
+	switch entryType {
+	case EntryTypeFile:
+		return func(entry IEntry) bool {
+			return !entry.IsSymlink() && !entry.IsDirectory()
+		}, nil
+	case EntryTypeDir:
+		return func(entry IEntry) bool {
+			return !entry.IsSymlink() && entry.IsDirectory()
+		}, nil
+	case EntryTypeSymlink:
+		return func(entry IEntry) bool {
+			return entry.IsSymlink()
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown entry type %q, expected %s, %s or %s",
+		entryType, EntryTypeFile, EntryTypeDir, EntryTypeSymlink)
+}
+
+// NewExtensionMatcher creates a matcher for the entries with the same extension and the same
+// kind (directory or not) as the given entry.
+func NewExtensionMatcher(reference IEntry) EntryMatcher {
+	return func(entry IEntry) bool {
+		return entry.IsDirectory() == reference.IsDirectory() && entry.GetExt() == reference.GetExt()
+	}
+}
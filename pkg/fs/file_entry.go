@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/djherbis/times"
@@ -22,7 +23,10 @@ type IEntry interface {
 	GetPermissions() string
 	IsDirectory() bool
 	IsSymlink() bool
+	IsBrokenSymlink() bool
+	GetLinkTarget() string
 	GetChangeTime() time.Time
+	GetError() error
 }
 
 // Entry contains information about a file or directory.
@@ -36,6 +40,12 @@ type Entry struct {
 	ext         string
 	permissions string
 	changeTime  time.Time
+
+	// linkTarget is the target of a symlink, isBrokenSymlink is set if it can't be followed
+	linkTarget      string
+	isBrokenSymlink bool
+	// err is the error which prevented reading the information of the entry
+	err error
 }
 
 // GetName returns the name of the entry.
@@ -73,6 +83,22 @@ func (e *Entry) IsSymlink() bool {
 	return e.isSymlink
 }
 
+// IsBrokenSymlink returns true if the current file is a symlink whose target doesn't exist
+func (e *Entry) IsBrokenSymlink() bool {
+	return e.isBrokenSymlink
+}
+
+// GetError returns the error which prevented reading the information of the entry, nil if the
+// information was read
+func (e *Entry) GetError() error {
+	return e.err
+}
+
+// GetLinkTarget returns the target of the symlink as written in the link, empty for other files
+func (e *Entry) GetLinkTarget() string {
+	return e.linkTarget
+}
+
 // File represents a file.
 type File struct {
 	*Entry
@@ -86,6 +112,10 @@ func (*File) IsDirectory() bool {
 // Directory represents a directory.
 type Directory struct {
 	*Entry
+
+	// contentSize is the recursive size of the content, hasContentSize is set once it is computed
+	contentSize    int64
+	hasContentSize bool
 }
 
 // IsDirectory always returns true.
@@ -93,6 +123,18 @@ func (*Directory) IsDirectory() bool {
 	return true
 }
 
+// SetContentSize sets the recursive size of the content of the directory
+func (d *Directory) SetContentSize(size int64) {
+	d.contentSize = size
+	d.hasContentSize = true
+}
+
+// GetContentSize returns the recursive size of the content of the directory and whether it was
+// computed
+func (d *Directory) GetContentSize() (int64, bool) {
+	return d.contentSize, d.hasContentSize
+}
+
 // LoadEntries loads the entries of the given directory.
 func LoadEntries(path string,
 	showHidden bool,
@@ -124,19 +166,34 @@ func LoadEntries(path string,
 				continue
 			}
 
-			return nil, err
+			// Keep the entries which can't be read, the rest of the directory is still listed
+			entry = newErrorEntry(path, name, err)
 		}
 
 		entries = append(entries, entry)
 	}
 
-	getEntrySort(types.SortType(sortAlgorithm)).sort(entries, sortReverse, sortIgnoreCase, sortIgnoreDiacritics)
+	SortEntries(entries, sortAlgorithm, sortReverse, sortIgnoreCase, sortIgnoreDiacritics)
 
 	return entries, nil
 }
 
+// SortEntries sorts the entries in place with the given sort algorithm.
+func SortEntries(entries []IEntry,
+	sortAlgorithm string,
+	sortReverse bool,
+	sortIgnoreCase bool,
+	sortIgnoreDiacritics bool,
+) {
+	getEntrySort(types.SortType(sortAlgorithm)).sort(entries, sortReverse, sortIgnoreCase, sortIgnoreDiacritics)
+}
+
 // loadEntry loads the entry of the given file path and file name.
 func loadEntry(path, name string, showHidden bool) (IEntry, error) {
+	if !showHidden && isHidden(name) {
+		return nil, errFileNotFound
+	}
+
 	fpath := filepath.Join(path, name)
 
 	lstat, err := getFileInfo(fpath)
@@ -144,10 +201,6 @@ func loadEntry(path, name string, showHidden bool) (IEntry, error) {
 		return nil, err
 	}
 
-	if !showHidden && isHidden(name) {
-		return nil, errFileNotFound
-	}
-
 	var ct time.Time
 
 	ts := times.Get(lstat)
@@ -161,29 +214,34 @@ func loadEntry(path, name string, showHidden bool) (IEntry, error) {
 	size := lstat.Size()
 	permissions := lstat.Mode().String()[1:]
 
+	var linkTarget string
+
+	isBrokenSymlink := false
+
 	isSymlink := (lstat.Mode() & os.ModeSymlink) != 0
 	if isSymlink {
-		linkTarget, err := evalSymlinks(fpath)
+		linkTarget, err = readLink(fpath)
 		if err != nil {
 			return nil, err
 		}
 
-		linkTargetLstat, err := getFileInfo(linkTarget)
-		if err != nil {
+		// Broken symlinks are listed as files so that they can be removed or renamed
+		linkTargetStat, err := os.Stat(fpath)
+		switch {
+		case err == nil:
+			isDir = linkTargetStat.IsDir()
+		case isBrokenSymlinkError(err):
+			isBrokenSymlink = true
+		default:
 			return nil, err
 		}
-
-		isDir = linkTargetLstat.IsDir()
 	}
 
-	var ext string
-	if ext = filepath.Ext(fpath); ext != "" {
-		ext = ext[1:]
-	}
+	ext := getExtension(fpath)
 
 	if isDir {
 		return &Directory{
-			&Entry{
+			Entry: &Entry{
 				name:        name,
 				path:        fpath,
 				size:        size,
@@ -191,6 +249,7 @@ func loadEntry(path, name string, showHidden bool) (IEntry, error) {
 				ext:         ext,
 				changeTime:  ct,
 				isSymlink:   isSymlink,
+				linkTarget:  linkTarget,
 			},
 		}, nil
 	}
@@ -204,10 +263,36 @@ func loadEntry(path, name string, showHidden bool) (IEntry, error) {
 			ext:         ext,
 			changeTime:  ct,
 			isSymlink:   isSymlink,
+			linkTarget:  linkTarget,
+
+			isBrokenSymlink: isBrokenSymlink,
 		},
 	}, nil
 }
 
+// newErrorEntry creates the entry of a file whose information can't be read
+func newErrorEntry(path, name string, err error) IEntry {
+	fpath := filepath.Join(path, name)
+
+	return &File{
+		&Entry{
+			name: name,
+			path: fpath,
+			ext:  getExtension(fpath),
+			err:  err,
+		},
+	}
+}
+
+// getExtension returns the extension of the file path without the dot
+func getExtension(fpath string) string {
+	if ext := filepath.Ext(fpath); ext != "" {
+		return ext[1:]
+	}
+
+	return ""
+}
+
 // getFileInfo get file information from the given file path
 func getFileInfo(fpath string) (os.FileInfo, error) {
 	lstat, err := os.Lstat(fpath)
@@ -222,12 +307,11 @@ func getFileInfo(fpath string) (os.FileInfo, error) {
 	return lstat, nil
 }
 
-// evalSymlinks get link target of the given symlinks
-func evalSymlinks(fpath string) (string, error) {
-	linkTarget, err := filepath.EvalSymlinks(fpath)
+// readLink get link target of the given symlink
+func readLink(fpath string) (string, error) {
+	linkTarget, err := os.Readlink(fpath)
 	if err != nil {
-		var pathError *os.PathError
-		if errors.As(err, &pathError) {
+		if os.IsNotExist(err) {
 			return "", errFileNotFound
 		}
 
@@ -237,6 +321,14 @@ func evalSymlinks(fpath string) (string, error) {
 	return linkTarget, nil
 }
 
+// isBrokenSymlinkError returns true if following a symlink failed because its target doesn't
+// exist, one of its parents is not a directory or the links form a loop
+func isBrokenSymlinkError(err error) bool {
+	return errors.Is(err, os.ErrNotExist) ||
+		errors.Is(err, syscall.ENOTDIR) ||
+		errors.Is(err, syscall.ELOOP)
+}
+
 // isHidden returns true if the given name is a hidden file.
 func isHidden(filename string) bool {
 	return filename[0:1] == "."
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+
+package fs
+
+import "errors"
+
+// GetFreeSpace is not supported on this platform
+func GetFreeSpace(_ string) (uint64, error) {
+	return 0, errors.New("free space is not supported on this platform")
+}
@@ -0,0 +1,16 @@
+//go:build linux || darwin || freebsd
+
+package fs
+
+import "syscall"
+
+// GetFreeSpace returns the space available to the user on the file system of the given path
+func GetFreeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	// The types of the fields depend on the platform
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -24,7 +24,24 @@ func (sizeEntrySort) sort(entries []IEntry, reverse bool, ignoreCase bool, ignor
 		}
 
 		if entry1.IsDirectory() && entry2.IsDirectory() {
-			// If 2 entries are both directory or file, then sort by their name
+			// Directories whose content size is computed are sorted by it, before the others
+			size1, hasSize1 := getContentSize(entry1)
+			size2, hasSize2 := getContentSize(entry2)
+
+			if hasSize1 && hasSize2 && size1 != size2 {
+				s := size1 > size2
+				if reverse {
+					s = !s
+				}
+
+				return s
+			}
+
+			if hasSize1 != hasSize2 {
+				return hasSize1
+			}
+
+			// Otherwise sort by their name
 			name1 := normalize(entry1.GetName(), ignoreCase, ignoreDiacritics)
 			name2 := normalize(entry2.GetName(), ignoreCase, ignoreDiacritics)
 
@@ -39,3 +56,12 @@ func (sizeEntrySort) sort(entries []IEntry, reverse bool, ignoreCase bool, ignor
 		return false
 	})
 }
+
+// getContentSize returns the recursive size of a directory and whether it was computed
+func getContentSize(entry IEntry) (int64, bool) {
+	if directory, ok := entry.(*Directory); ok {
+		return directory.GetContentSize()
+	}
+
+	return 0, false
+}
@@ -0,0 +1,211 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileName is the name of the history file in the fm state directory
+const fileName = "history"
+
+// History stores the input history of each mode. The history is saved in the background, mu
+// guards the entries and saveMu runs the saves one at a time.
+type History struct {
+	path string
+
+	mu sync.Mutex
+	// size is the maximum number of entries kept per mode
+	size    int
+	entries map[string][]string
+
+	saveMu sync.Mutex
+}
+
+// NewHistory creates an empty history stored at the given path
+func NewHistory(path string, size int) *History {
+	return &History{
+		path:    path,
+		size:    size,
+		entries: make(map[string][]string),
+	}
+}
+
+// GetHistoryFilePath returns the path of the history file:
+// $XDG_STATE_HOME/fm/history, or ~/.local/state/fm/history if XDG_STATE_HOME is not set
+func GetHistoryFilePath() string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		stateDir = filepath.Join(os.Getenv("HOME"), ".local", "state")
+	}
+
+	return filepath.Join(stateDir, "fm", fileName)
+}
+
+// SetSize updates the maximum number of entries kept per mode
+func (h *History) SetSize(size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.size = size
+
+	for mode := range h.entries {
+		h.entries[mode] = h.truncate(h.entries[mode])
+	}
+}
+
+// Load reads the history file, a missing file is an empty history
+func (h *History) Load() error {
+	entries, err := h.read()
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for mode := range entries {
+		entries[mode] = h.truncate(entries[mode])
+	}
+
+	h.entries = entries
+
+	return nil
+}
+
+// read returns the entries of the history file, a missing file has no entries
+func (h *History) read() (map[string][]string, error) {
+	entries := make(map[string][]string)
+
+	content, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Save merges the history file with the entries of this instance, so that the entries added by
+// other instances are kept, then replaces the file. It can run in the background.
+func (h *History) Save() error {
+	const perm = 0600
+
+	h.saveMu.Lock()
+	defer h.saveMu.Unlock()
+
+	dir := filepath.Dir(h.path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	fileEntries, readErr := h.read()
+
+	h.mu.Lock()
+	// A history file which can't be read is replaced
+	if readErr == nil {
+		for mode, entries := range fileEntries {
+			h.entries[mode] = h.truncate(mergeEntries(entries, h.entries[mode]))
+		}
+	}
+
+	content, err := json.MarshalIndent(h.entries, "", "  ")
+	h.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file renamed over the history file, so that the file is never left half
+	// written
+	file, err := os.CreateTemp(dir, fileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(file.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), h.path)
+}
+
+// Add appends an entry to the history of the mode, Save writes it to the history file.
+// Empty entries are ignored, a previous identical entry is removed.
+func (h *History) Add(mode string, entry string) {
+	if entry == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := make([]string, 0, len(h.entries[mode])+1)
+	for _, e := range h.entries[mode] {
+		if e != entry {
+			entries = append(entries, e)
+		}
+	}
+
+	h.entries[mode] = h.truncate(append(entries, entry))
+}
+
+// mergeEntries returns the entries of the file followed by the entries of this instance which are
+// not in the file, the last entry added by this instance stays the newest
+func mergeEntries(fileEntries, entries []string) []string {
+	merged := make([]string, 0, len(fileEntries)+len(entries))
+	seen := make(map[string]bool, len(fileEntries)+len(entries))
+
+	for _, entry := range entries {
+		seen[entry] = true
+	}
+
+	for _, entry := range fileEntries {
+		if !seen[entry] {
+			merged = append(merged, entry)
+		}
+	}
+
+	return append(merged, entries...)
+}
+
+// GetEntries returns the history of the mode from the oldest to the newest entry
+func (h *History) GetEntries(mode string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.entries[mode]
+}
+
+// truncate keeps the newest entries within the size limit
+func (h *History) truncate(entries []string) []string {
+	if h.size <= 0 {
+		return nil
+	}
+
+	if len(entries) <= h.size {
+		return entries
+	}
+
+	return entries[len(entries)-h.size:]
+}
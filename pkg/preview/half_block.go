@@ -0,0 +1,68 @@
+package preview
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Characters and escape sequences of the half block rendering
+const (
+	upperHalfBlock = "▀"
+	lowerHalfBlock = "▄"
+	resetSequence  = "\x1b[0m"
+)
+
+// RenderHalfBlock renders an image with Unicode half blocks, each cell shows two vertically
+// stacked pixels with 24-bit colors. Images larger than maxCols x maxRows cells are shrunk.
+func RenderHalfBlock(img image.Image, maxCols, maxRows int) string {
+	bounds := img.Bounds()
+
+	cols, rows := fitCells(bounds.Dx(), bounds.Dy(), 1, 2, maxCols, maxRows)
+	if cols == 0 || rows == 0 {
+		return ""
+	}
+
+	pixels := scaleImage(img, cols, rows*2)
+	lines := make([]string, 0, rows)
+
+	for row := range rows {
+		var sb strings.Builder
+
+		for col := range cols {
+			writeHalfBlock(&sb, pixels.NRGBAAt(col, row*2), pixels.NRGBAAt(col, row*2+1))
+		}
+
+		sb.WriteString(resetSequence)
+		lines = append(lines, sb.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// writeHalfBlock writes a cell showing the top and the bottom pixels, transparent pixels are
+// left to the background of the terminal
+func writeHalfBlock(sb *strings.Builder, top, bottom color.NRGBA) {
+	topVisible := top.A >= alphaThreshold
+	bottomVisible := bottom.A >= alphaThreshold
+
+	switch {
+	case topVisible && bottomVisible:
+		sb.WriteString("\x1b[38;2;" + formatRGB(top) + ";48;2;" + formatRGB(bottom) + "m")
+		sb.WriteString(upperHalfBlock)
+	case topVisible:
+		sb.WriteString("\x1b[49;38;2;" + formatRGB(top) + "m")
+		sb.WriteString(upperHalfBlock)
+	case bottomVisible:
+		sb.WriteString("\x1b[49;38;2;" + formatRGB(bottom) + "m")
+		sb.WriteString(lowerHalfBlock)
+	default:
+		sb.WriteString(resetSequence + " ")
+	}
+}
+
+// formatRGB formats the components of a color as parameters of an SGR sequence
+func formatRGB(c color.NRGBA) string {
+	return strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B))
+}
@@ -0,0 +1,32 @@
+package preview
+
+import (
+	"testing"
+)
+
+func TestRenderHalfBlock(t *testing.T) {
+	tests := []struct {
+		name             string
+		width, height    int
+		maxCols, maxRows int
+	}{
+		{"fits", 6, 4, 10, 10},
+		{"odd height", 4, 5, 10, 10},
+		{"shrunk", 32, 16, 8, 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			img := newTestImage(test.width, test.height)
+			output := RenderHalfBlock(img, test.maxCols, test.maxRows)
+
+			assertGolden(t, "half_block_"+test.name, output)
+		})
+	}
+}
+
+func TestRenderHalfBlockNoSpace(t *testing.T) {
+	if output := RenderHalfBlock(newTestImage(4, 4), 0, 4); output != "" {
+		t.Errorf("got %q, want an empty output", output)
+	}
+}
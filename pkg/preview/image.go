@@ -0,0 +1,136 @@
+package preview
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"  // register the GIF decoder
+	_ "image/jpeg" // register the JPEG decoder
+	_ "image/png"  // register the PNG decoder
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// alphaThreshold is the alpha below which a pixel is drawn as transparent
+const alphaThreshold = 0x80
+
+// maxImagePixels is the number of pixels above which images are not decoded, a small compressed
+// file can hold a huge image
+const maxImagePixels = 40 * 1000 * 1000
+
+// ErrImageTooLarge is returned when an image has too many pixels to be decoded
+var ErrImageTooLarge = errors.New("image too large")
+
+// imageExtensions are the extensions of the images which can be decoded
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+}
+
+// IsImage returns whether the file is an image which can be previewed, based on its extension
+func IsImage(path string) bool {
+	return imageExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// DecodeImage decodes an image file, the first frame of animated images is used. The dimensions
+// are read first and ErrImageTooLarge is returned for the images with too many pixels.
+func DecodeImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	imgConfig, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(imgConfig.Width)*int64(imgConfig.Height) > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(file)
+
+	return img, err
+}
+
+// fitCells returns the number of columns and rows covered by an image of the given size, with
+// cells of cellWidth x cellHeight pixels. Images larger than maxCols x maxRows are shrunk,
+// keeping their aspect ratio.
+func fitCells(width, height, cellWidth, cellHeight, maxCols, maxRows int) (int, int) {
+	if width <= 0 || height <= 0 || maxCols <= 0 || maxRows <= 0 {
+		return 0, 0
+	}
+
+	scale := math.Min(1, math.Min(
+		float64(maxCols*cellWidth)/float64(width),
+		float64(maxRows*cellHeight)/float64(height),
+	))
+
+	cols := int(math.Ceil(float64(width) * scale / float64(cellWidth)))
+	rows := int(math.Ceil(float64(height) * scale / float64(cellHeight)))
+
+	return min(max(cols, 1), maxCols), min(max(rows, 1), maxRows)
+}
+
+// scaleImage resizes an image, each pixel is the average of the pixels of the source area it
+// covers
+func scaleImage(src image.Image, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	bounds := src.Bounds()
+
+	for y := range height {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+
+		for x := range width {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+			dst.SetNRGBA(x, y, averageColor(src, x0, y0, x1, y1))
+		}
+	}
+
+	return dst
+}
+
+// averageColor returns the average color of an area of an image
+func averageColor(src image.Image, x0, y0, x1, y1 int) color.NRGBA {
+	var r, g, b, a, count uint64
+
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			// The components are premultiplied by the alpha
+			pr, pg, pb, pa := src.At(x, y).RGBA()
+			r += uint64(pr)
+			g += uint64(pg)
+			b += uint64(pb)
+			a += uint64(pa)
+			count++
+		}
+	}
+
+	if a == 0 {
+		return color.NRGBA{}
+	}
+
+	return color.NRGBA{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+		A: uint8(a / count >> 8),
+	}
+}
@@ -0,0 +1,85 @@
+package preview
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update regenerates the golden files: go test ./pkg/preview -update
+var update = flag.Bool("update", false, "update the golden files")
+
+// newTestImage returns a gradient image, the pixels of the top left quarter are transparent
+func newTestImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		for x := range width {
+			alpha := uint8(0xff)
+			if x < width/2 && y < height/2 {
+				alpha = 0
+			}
+
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(x * 0xff / max(width-1, 1)),
+				G: uint8(y * 0xff / max(height-1, 1)),
+				B: 0x80,
+				A: alpha,
+			})
+		}
+	}
+
+	return img
+}
+
+// assertGolden compares the output with the content of testdata/<name>.golden, the spaces of the
+// name are replaced with underscores
+func assertGolden(t *testing.T, name string, output string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", strings.ReplaceAll(name, " ", "_")+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output != string(expected) {
+		t.Errorf("output doesn't match %s\ngot:  %q\nwant: %q", path, output, expected)
+	}
+}
+
+func TestFitCells(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		width, height, cellWidth, cellHeight int
+		maxCols, maxRows                     int
+		cols, rows                           int
+	}{
+		{"fits", 8, 8, 1, 2, 10, 10, 8, 4},
+		{"shrunk by width", 40, 10, 1, 2, 10, 10, 10, 2},
+		{"shrunk by height", 10, 40, 1, 2, 10, 10, 5, 10},
+		{"at least one cell", 100, 1, 1, 2, 10, 10, 10, 1},
+		{"empty image", 0, 10, 1, 2, 10, 10, 0, 0},
+		{"no space", 10, 10, 1, 2, 0, 10, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cols, rows := fitCells(test.width, test.height, test.cellWidth, test.cellHeight,
+				test.maxCols, test.maxRows)
+			if cols != test.cols || rows != test.rows {
+				t.Errorf("got %dx%d cells, want %dx%d", cols, rows, test.cols, test.rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"strings"
+)
+
+// Kitty graphics protocol, see https://sw.kovidgoyal.net/kitty/graphics-protocol/
+const (
+	// kittyImageID is the id of the previewed image, drawing an image with the same id replaces
+	// the previous one
+	kittyImageID = 1
+	// kittyChunkSize is the maximum size of the payload of an escape sequence
+	kittyChunkSize = 4096
+	// kittyCellWidth and kittyCellHeight are the assumed size of a cell in pixels, used to choose
+	// the number of cells covered by an image
+	kittyCellWidth  = 10
+	kittyCellHeight = 20
+)
+
+// KittyDeleteImages is the escape sequence deleting the images drawn with the kitty graphics
+// protocol. Images are not part of the text, they stay on the screen until they are deleted.
+const KittyDeleteImages = "\x1b_Ga=d,d=A,q=2\x1b\\"
+
+// RenderKitty renders an image with the kitty graphics protocol. The image is transmitted as
+// PNG and drawn over the cells of the first line without moving the cursor, the lines are
+// filled with spaces to reserve the cells. Images larger than maxCols x maxRows cells are
+// shrunk.
+func RenderKitty(img image.Image, maxCols, maxRows int) (string, error) {
+	bounds := img.Bounds()
+
+	cols, rows := fitCells(
+		bounds.Dx(), bounds.Dy(), kittyCellWidth, kittyCellHeight, maxCols, maxRows)
+	if cols == 0 || rows == 0 {
+		return "", nil
+	}
+
+	// Don't transmit more pixels than the cells can show
+	scale := math.Min(
+		float64(cols*kittyCellWidth)/float64(bounds.Dx()),
+		float64(rows*kittyCellHeight)/float64(bounds.Dy()),
+	)
+	if scale < 1 {
+		img = scaleImage(img,
+			max(int(float64(bounds.Dx())*scale), 1),
+			max(int(float64(bounds.Dy())*scale), 1),
+		)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", cols)
+	}
+
+	lines[0] = kittyTransmit(base64.StdEncoding.EncodeToString(buf.Bytes()), cols, rows) + lines[0]
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// kittyTransmit returns the escape sequences transmitting and drawing a PNG image over
+// cols x rows cells, the payload is split in chunks
+func kittyTransmit(payload string, cols, rows int) string {
+	var sb strings.Builder
+
+	for offset := 0; offset < len(payload); offset += kittyChunkSize {
+		end := min(offset+kittyChunkSize, len(payload))
+
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+
+		if offset == 0 {
+			fmt.Fprintf(&sb, "\x1b_Ga=T,f=100,i=%d,p=1,c=%d,r=%d,C=1,q=2,m=%d;",
+				kittyImageID, cols, rows, more)
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;", more)
+		}
+
+		sb.WriteString(payload[offset:end])
+		sb.WriteString("\x1b\\")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,163 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderKitty(t *testing.T) {
+	tests := []struct {
+		name             string
+		width, height    int
+		maxCols, maxRows int
+		// cols and rows are the cells covered by the image, pixelWidth and pixelHeight the size
+		// of the transmitted image
+		cols, rows              int
+		pixelWidth, pixelHeight int
+	}{
+		{"fits", 20, 20, 10, 10, 2, 1, 20, 20},
+		{"shrunk", 200, 100, 4, 4, 4, 1, 40, 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := newTestImage(test.width, test.height)
+
+			output, err := RenderKitty(src, test.maxCols, test.maxRows)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cols, rows, img, lines := parseKittyOutput(t, output)
+			if cols != test.cols || rows != test.rows {
+				t.Errorf("got %dx%d cells, want %dx%d", cols, rows, test.cols, test.rows)
+			}
+
+			// The cells covered by the image are reserved with spaces
+			if len(lines) != rows {
+				t.Errorf("got %d lines, want %d", len(lines), rows)
+			}
+
+			for i, line := range lines {
+				if line != strings.Repeat(" ", cols) {
+					t.Errorf("line %d is %q, want %d spaces", i, line, cols)
+				}
+			}
+
+			bounds := img.Bounds()
+			if bounds.Dx() != test.pixelWidth || bounds.Dy() != test.pixelHeight {
+				t.Fatalf("got a %dx%d image, want %dx%d",
+					bounds.Dx(), bounds.Dy(), test.pixelWidth, test.pixelHeight)
+			}
+
+			expected := image.Image(src)
+			if test.pixelWidth != test.width || test.pixelHeight != test.height {
+				expected = scaleImage(src, test.pixelWidth, test.pixelHeight)
+			}
+
+			assertSamePixels(t, img, expected)
+		})
+	}
+}
+
+// parseKittyOutput returns the cells covered by the image drawn by RenderKitty, the decoded image
+// and the lines without the escape sequences
+func parseKittyOutput(t *testing.T, output string) (int, int, image.Image, []string) {
+	t.Helper()
+
+	const start, end = "\x1b_G", "\x1b\\"
+
+	lines := strings.Split(output, "\n")
+	text := lines[0]
+
+	var (
+		params  string
+		payload strings.Builder
+	)
+
+	for strings.HasPrefix(text, start) {
+		sequence, rest, found := strings.Cut(text[len(start):], end)
+		if !found {
+			t.Fatalf("unterminated escape sequence in %q", lines[0])
+		}
+
+		control, data, _ := strings.Cut(sequence, ";")
+		if params == "" {
+			params = control
+		}
+
+		payload.WriteString(data)
+		text = rest
+	}
+
+	lines[0] = text
+
+	var cols, rows int
+
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(param, "=")
+		switch key {
+		case "c":
+			cols, _ = strconv.Atoi(value)
+		case "r":
+			rows, _ = strconv.Atoi(value)
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cols, rows, img, lines
+}
+
+// assertSamePixels compares the colors of the pixels of two images of the same size
+func assertSamePixels(t *testing.T, img, expected image.Image) {
+	t.Helper()
+
+	bounds := img.Bounds()
+	expectedBounds := expected.Bounds()
+
+	for y := range bounds.Dy() {
+		for x := range bounds.Dx() {
+			got := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			want := color.NRGBAModel.Convert(expected.At(expectedBounds.Min.X+x, expectedBounds.Min.Y+y))
+
+			if got != want {
+				t.Fatalf("pixel (%d, %d) is %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestKittyTransmit(t *testing.T) {
+	tests := []struct {
+		name        string
+		payloadSize int
+	}{
+		{"single chunk", 16},
+		{"exact chunk", kittyChunkSize},
+		{"several chunks", kittyChunkSize*2 + 16},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payload := strings.Repeat("ABCD", test.payloadSize/4)
+			output := kittyTransmit(payload, 3, 2)
+
+			assertGolden(t, "kitty_transmit_"+test.name, output)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/dinhhuy258/fm/pkg/types"
+)
+
+// tooLargeImage is the preview of the images which are not decoded because of their size
+const tooLargeImage = "Image too large to preview"
+
+// Limits of the text preview
+const (
+	// maxTextSize is the number of bytes read to preview a text file
+	maxTextSize = 64 * 1024
+	// tabWidth is the number of spaces a tab is expanded to
+	tabWidth = 4
+)
+
+// Options configures the rendering of a preview
+type Options struct {
+	// Width and Height are the size of the preview in cells
+	Width  int
+	Height int
+	// ImageProtocol is the resolved protocol used to draw images
+	ImageProtocol types.ImageProtocol
+	// MaxImageSize is the size in bytes above which images are not decoded, 0 for no limit
+	MaxImageSize int64
+}
+
+// Preview is the rendered preview of a file
+type Preview struct {
+	// Content has at most Height lines of at most Width cells
+	Content string
+	// KittyImage is set if the content draws an image with the kitty graphics protocol
+	KittyImage bool
+}
+
+// Render renders the preview of a file: directories list their entries, images are drawn with
+// the image protocol and text files show their first lines
+func Render(path string, options Options) (Preview, error) {
+	if options.Width <= 0 || options.Height <= 0 {
+		return Preview{}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if target, linkErr := os.Readlink(path); linkErr == nil {
+			content := truncate(sanitize("Broken symlink to "+target), options.Width)
+
+			return Preview{Content: content}, nil
+		}
+
+		return Preview{}, err
+	}
+
+	switch {
+	case info.IsDir():
+		content, err := renderDirectory(path, options.Width, options.Height)
+
+		return Preview{Content: content}, err
+	case IsImage(path) && options.ImageProtocol != types.ImageProtocolNone:
+		if options.MaxImageSize > 0 && info.Size() > options.MaxImageSize {
+			return Preview{Content: tooLargeImage}, nil
+		}
+
+		return renderImage(path, options)
+	case !info.Mode().IsRegular():
+		return Preview{Content: "Special file"}, nil
+	default:
+		content, err := renderText(path, options.Width, options.Height)
+
+		return Preview{Content: content}, err
+	}
+}
+
+// renderImage decodes an image and renders it with the image protocol
+func renderImage(path string, options Options) (Preview, error) {
+	img, err := DecodeImage(path)
+	if errors.Is(err, ErrImageTooLarge) {
+		return Preview{Content: tooLargeImage}, nil
+	}
+
+	if err != nil {
+		return Preview{}, err
+	}
+
+	bounds := img.Bounds()
+	// Keep the last line for the size of the image
+	maxRows := max(options.Height-1, 1)
+	info := truncate(strconv.Itoa(bounds.Dx())+"x"+strconv.Itoa(bounds.Dy()), options.Width)
+
+	if options.ImageProtocol == types.ImageProtocolKitty {
+		content, err := RenderKitty(img, options.Width, maxRows)
+		if err != nil {
+			return Preview{}, err
+		}
+
+		return Preview{
+			Content:    appendImageInfo(content, info, options.Height),
+			KittyImage: true,
+		}, nil
+	}
+
+	content := RenderHalfBlock(img, options.Width, maxRows)
+
+	return Preview{Content: appendImageInfo(content, info, options.Height)}, nil
+}
+
+// appendImageInfo adds the information about the image below it if there is space left
+func appendImageInfo(content string, info string, height int) string {
+	if strings.Count(content, "\n")+1 >= height {
+		return content
+	}
+
+	return content + "\n" + info
+}
+
+// renderDirectory lists the entries of a directory, directories end with a slash
+func renderDirectory(path string, width, height int) (string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	if len(entries) == 0 {
+		return "Empty directory", nil
+	}
+
+	lines := make([]string, 0, min(len(entries), height))
+
+	for i, entry := range entries {
+		if len(lines) == height-1 && len(entries) > height {
+			lines = append(lines, truncate(fmt.Sprintf("… %d more", len(entries)-i), width))
+
+			break
+		}
+
+		name := sanitize(entry.Name())
+		if entry.IsDir() {
+			name += "/"
+		}
+
+		lines = append(lines, truncate(name, width))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// renderText shows the first lines of a text file, binary files are not shown
+func renderText(path string, width, height int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxTextSize))
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) == maxTextSize {
+		data = trimIncompleteRune(data)
+	}
+
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "Binary file", nil
+	}
+
+	lines := strings.SplitN(string(data), "\n", height+1)
+	lines = lines[:min(len(lines), height)]
+
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		line = strings.ReplaceAll(line, "\t", strings.Repeat(" ", tabWidth))
+		lines[i] = truncate(sanitize(line), width)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// trimIncompleteRune removes the bytes of a rune cut at the end of the data
+func trimIncompleteRune(data []byte) []byte {
+	for i := 0; i < utf8.UTFMax && i < len(data); i++ {
+		if utf8.RuneStart(data[len(data)-1-i]) {
+			if !utf8.FullRune(data[len(data)-1-i:]) {
+				return data[:len(data)-1-i]
+			}
+
+			break
+		}
+	}
+
+	return data
+}
+
+// sanitize replaces the control characters which would break the layout of the preview
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return '?'
+		}
+
+		return r
+	}, s)
+}
+
+// truncate cuts a string to the given width in cells
+func truncate(s string, width int) string {
+	if uniseg.StringWidth(s) <= width {
+		return s
+	}
+
+	var sb strings.Builder
+
+	state := -1
+	remaining := s
+	used := 0
+
+	for len(remaining) > 0 {
+		var (
+			cluster   string
+			charWidth int
+		)
+
+		cluster, remaining, charWidth, state = uniseg.FirstGraphemeClusterInString(remaining, state)
+		if used+charWidth > width {
+			break
+		}
+
+		sb.WriteString(cluster)
+		used += charWidth
+	}
+
+	return sb.String()
+}
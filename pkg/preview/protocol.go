@@ -0,0 +1,45 @@
+package preview
+
+import (
+	"os"
+	"strings"
+
+	"github.com/dinhhuy258/fm/pkg/types"
+)
+
+// ResolveImageProtocol returns the protocol used to draw images, the auto protocol is resolved
+// from the environment
+func ResolveImageProtocol(protocol types.ImageProtocol) types.ImageProtocol {
+	if protocol != types.ImageProtocolAuto {
+		return protocol
+	}
+
+	return DetectImageProtocol(os.Getenv)
+}
+
+// DetectImageProtocol returns the kitty graphics protocol if the terminal described by the
+// environment supports it, and half blocks otherwise. Terminal multiplexers don't pass the
+// graphics through, so half blocks are used inside them.
+func DetectImageProtocol(getenv func(key string) string) types.ImageProtocol {
+	term := getenv("TERM")
+
+	if getenv("TMUX") != "" || strings.HasPrefix(term, "screen") {
+		return types.ImageProtocolHalfBlock
+	}
+
+	if getenv("KITTY_WINDOW_ID") != "" {
+		return types.ImageProtocolKitty
+	}
+
+	switch term {
+	case "xterm-kitty", "xterm-ghostty":
+		return types.ImageProtocolKitty
+	}
+
+	switch getenv("TERM_PROGRAM") {
+	case "ghostty", "WezTerm":
+		return types.ImageProtocolKitty
+	}
+
+	return types.ImageProtocolHalfBlock
+}
@@ -0,0 +1,42 @@
+package preview
+
+import (
+	"testing"
+
+	"github.com/dinhhuy258/fm/pkg/types"
+)
+
+func TestDetectImageProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		protocol types.ImageProtocol
+	}{
+		{"unknown terminal", map[string]string{"TERM": "xterm-256color"}, types.ImageProtocolHalfBlock},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, types.ImageProtocolKitty},
+		{"kitty window", map[string]string{"KITTY_WINDOW_ID": "1"}, types.ImageProtocolKitty},
+		{"ghostty", map[string]string{"TERM": "xterm-ghostty"}, types.ImageProtocolKitty},
+		{"wezterm", map[string]string{"TERM_PROGRAM": "WezTerm"}, types.ImageProtocolKitty},
+		{
+			"kitty in tmux",
+			map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux", "KITTY_WINDOW_ID": "1"},
+			types.ImageProtocolHalfBlock,
+		},
+		{
+			"kitty in screen",
+			map[string]string{"TERM": "screen-256color", "KITTY_WINDOW_ID": "1"},
+			types.ImageProtocolHalfBlock,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			protocol := DetectImageProtocol(func(key string) string {
+				return test.env[key]
+			})
+			if protocol != test.protocol {
+				t.Errorf("got %q, want %q", protocol, test.protocol)
+			}
+		})
+	}
+}
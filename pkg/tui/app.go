@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/dinhhuy258/fm/pkg/actions"
 	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/config/lua"
+	"github.com/dinhhuy258/fm/pkg/history"
 	"github.com/dinhhuy258/fm/pkg/pipe"
 	"github.com/dinhhuy258/fm/pkg/types"
 )
@@ -29,30 +32,74 @@ type Model struct {
 	notificationModel *NotificationModel
 	inputModel        *InputModel
 	helpModel         *HelpModel
+	selectionPanel    *SelectionPanelModel
+	logViewer         *LogViewerModel
+	previewModel      *PreviewModel
+	entryInfo         *EntryInfoModel
+	diskUsage         *DiskUsageModel
 
 	pipe          *pipe.Pipe
+	luaEngine     *lua.Lua
 	actionHandler *actions.ActionHandler
 	modeManager   *ModeManager
 	keyManager    *KeyManager
+	mouseManager  *MouseManager
+	history       *history.History
+
+	// directorySizes computes the recursive sizes of the directories in the background
+	directorySizes *DirectorySizeManager
+
+	// Window size and the layout applied for it
+	width         int
+	height        int
+	showBorder    bool
+	compactHeader bool
+	showPreview   bool
+
+	// quitting is set once the quit hooks ran, quitError is the error of the quit hooks
+	quitting  bool
+	quitError error
+
+	// whichKeySequenceID is the id of the pending key sequence whose next keys are shown
+	whichKeySequenceID int
+
+	// entryErrorSummary describes the entries of the loaded directory which can't be read, it is
+	// shown as a notification after the message is handled
+	entryErrorSummary string
+
+	// entriesLoaded is set when the entries of the listing were loaded, the sizes of their
+	// directories are computed after the message is handled
+	entriesLoaded bool
+
+	// directoryInfo caches the information about the current directory shown in the status lines
+	directoryInfo map[string]string
+
+	// Config file watching state
+	watchingConfig bool
+	configModTime  time.Time
 
 	// Styles for header and footer
 	titleStyle    lipgloss.Style
-	modeStyle     lipgloss.Style
 	helpHintStyle lipgloss.Style
 	borderStyle   lipgloss.Style
+	whichKeyStyle lipgloss.Style
+	header        *StatusLine
+	footer        *StatusLine
 }
 
 // NewModel creates a new root model
-func NewModel(pipe *pipe.Pipe) Model {
+func NewModel(pipe *pipe.Pipe, luaEngine *lua.Lua) Model {
 	explorerModel := NewExplorerModel()
 	notificationModel := NewNotificationModel()
 	inputModel := NewInputModel()
 
 	modeManager := NewModeManager()
 	helpModel := NewHelpModel(modeManager)
+	selectionPanel := NewSelectionPanelModel(explorerModel)
 	keyManager := NewKeyManager(modeManager)
 
 	actionHandler := actions.NewActionHandler()
+	inputHistory := history.NewHistory(history.GetHistoryFilePath(), config.AppConfig.General.HistorySize)
 
 	// Initialize sorting and display settings from config
 	showHidden := config.AppConfig.General.ShowHidden
@@ -62,17 +109,7 @@ func NewModel(pipe *pipe.Pipe) Model {
 		reverse = *config.AppConfig.General.Sorting.Reverse
 	}
 
-	titleStyle := lipgloss.NewStyle()
-	modeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(SecondaryTextColor))
-	helpHintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(SecondaryTextColor))
-	borderStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		PaddingLeft(1).
-		PaddingRight(1)
-
-	return Model{
+	m := Model{
 		currentPath:       "",
 		showHidden:        showHidden,
 		sortType:          sortType,
@@ -81,15 +118,59 @@ func NewModel(pipe *pipe.Pipe) Model {
 		notificationModel: notificationModel,
 		inputModel:        inputModel,
 		helpModel:         helpModel,
+		selectionPanel:    selectionPanel,
+		logViewer:         NewLogViewerModel(notificationModel),
+		previewModel:      NewPreviewModel(),
+		entryInfo:         NewEntryInfoModel(),
+		diskUsage:         NewDiskUsageModel(),
 		pipe:              pipe,
+		luaEngine:         luaEngine,
 		modeManager:       modeManager,
 		keyManager:        keyManager,
+		mouseManager:      NewMouseManager(),
+		directorySizes:    NewDirectorySizeManager(),
+		history:           inputHistory,
+		directoryInfo:     make(map[string]string),
 		actionHandler:     actionHandler,
-		titleStyle:        titleStyle,
-		modeStyle:         modeStyle,
-		helpHintStyle:     helpHintStyle,
-		borderStyle:       borderStyle,
+		watchingConfig:    config.AppConfig.General.WatchConfig,
+		configModTime:     getConfigModTime(),
 	}
+	m.initStyles()
+
+	return m
+}
+
+// initStyles creates the styles of the header, the footer and the frame from the current config
+func (m *Model) initStyles() {
+	generalConfig := config.AppConfig.General
+	frameColor, selFrameColor := getFrameColors()
+
+	m.titleStyle = fromStyleConfig(generalConfig.TitleStyle)
+	m.helpHintStyle = fromStyleConfig(generalConfig.FooterStyle)
+	m.header = NewStatusLine(generalConfig.HeaderFormat, generalConfig.InfoStyle)
+	m.footer = NewStatusLine(generalConfig.FooterFormat, generalConfig.FooterStyle)
+	m.borderStyle = newBorderStyle(frameColor)
+	m.whichKeyStyle = newBorderStyle(selFrameColor)
+}
+
+// reloadStyles rebuilds the styles of all components from the current config, e.g. after
+// the theme changed
+func (m *Model) reloadStyles() {
+	m.initStyles()
+	m.explorerModel.ReloadConfig()
+	m.notificationModel.ReloadConfig()
+	m.inputModel.ReloadConfig()
+	m.helpModel.ReloadConfig()
+	m.selectionPanel.ReloadConfig()
+	m.logViewer.ReloadConfig()
+	m.previewModel.ReloadConfig()
+	m.entryInfo.ReloadConfig()
+	m.diskUsage.ReloadConfig()
+}
+
+// GetQuitError returns the error of the quit hooks, it can only be reported after fm exited
+func (m Model) GetQuitError() error {
+	return m.quitError
 }
 
 // Init initializes the model
@@ -100,29 +181,154 @@ func (m Model) Init() tea.Cmd {
 		return tea.Quit
 	}
 
-	return func() tea.Msg {
-		return actions.ChangeDirectoryMessage{Path: wd}
+	var watchCmd tea.Cmd
+	if m.watchingConfig {
+		watchCmd = watchConfig()
 	}
+
+	return tea.Batch(
+		watchCmd,
+		m.checkConfig(),
+		m.loadHistory(),
+		tea.Sequence(
+			func() tea.Msg {
+				return actions.ChangeDirectoryMessage{Path: wd}
+			},
+			m.runHooks(config.EventStart, map[string]string{
+				"path": wd,
+			}),
+		),
+	)
+}
+
+// loadHistory loads the input history file
+func (m Model) loadHistory() tea.Cmd {
+	if err := m.history.Load(); err != nil {
+		return func() tea.Msg {
+			return actions.LogMessage{
+				Level:   actions.LogLevelError,
+				Message: fmt.Sprintf("Failed to load history: %v", err),
+			}
+		}
+	}
+
+	return nil
 }
 
 // Update handles incoming messages and updates the model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m.handleMessage(msg)
+	previousPath := m.currentPath
+	previousFocusPath := m.getFocusPath()
+
+	model, cmd := m.handleMessage(msg)
+
+	updatedModel, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+
+	// Keep the selection panel in sync with selections changed by messages, e.g. from the pipe
+	if updatedModel.selectionPanel.IsVisible() {
+		updatedModel.selectionPanel.Refresh()
+	}
+
+	if updatedModel.logViewer.IsVisible() {
+		updatedModel.logViewer.Refresh()
+	}
+
+	if updatedModel.entryErrorSummary != "" {
+		cmd = tea.Batch(cmd, updatedModel.notificationModel.ShowNotification(
+			NotificationWarning, updatedModel.entryErrorSummary))
+		updatedModel.entryErrorSummary = ""
+	}
+
+	if updatedModel.entriesLoaded {
+		// The sizes of the directories listed by disk usage are known
+		entries := updatedModel.explorerModel.entries
+		if updatedModel.diskUsage.IsActive() {
+			entries = nil
+		}
+
+		cmd = tea.Batch(cmd, updatedModel.directorySizes.Load(entries))
+		updatedModel.entriesLoaded = false
+	}
+
+	return updatedModel, tea.Batch(
+		cmd,
+		updatedModel.runStateHooks(previousPath, previousFocusPath),
+		updatedModel.loadPreview(),
+	)
+}
+
+// loadPreview shows the preview of the focused entry, it returns the command rendering the
+// preview if it is not cached
+func (m Model) loadPreview() tea.Cmd {
+	if !m.showPreview {
+		return nil
+	}
+
+	return m.previewModel.Load(m.explorerModel.GetFocusedEntry())
 }
 
 // View renders the UI
 func (m Model) View() string {
+	view := m.renderView()
+
+	if !m.isPreviewImageVisible() {
+		return m.previewModel.ClearImages(view)
+	}
+
+	return view
+}
+
+// isPreviewImageVisible returns whether the view shows an image drawn with the kitty graphics
+// protocol
+func (m Model) isPreviewImageVisible() bool {
+	return m.showPreview && m.previewModel.HasKittyImage() && !m.isOverlayVisible()
+}
+
+// isOverlayVisible returns whether a view is shown over the explorer
+func (m Model) isOverlayVisible() bool {
+	return m.helpModel.IsVisible() || m.selectionPanel.IsVisible() || m.logViewer.IsVisible() ||
+		m.entryInfo.IsVisible()
+}
+
+// renderView renders the main view or the visible overlay
+func (m Model) renderView() string {
 	// If help UI is visible, render it as an overlay
 	if m.helpModel.IsVisible() {
 		return m.helpModel.View()
 	}
 
+	if m.selectionPanel.IsVisible() {
+		return m.selectionPanel.View()
+	}
+
+	if m.logViewer.IsVisible() {
+		return m.logViewer.View()
+	}
+
+	if m.entryInfo.IsVisible() {
+		return m.entryInfo.View()
+	}
+
 	var sections []string
 
+	explorerView := m.explorerModel.View()
+	if m.isWhichKeyVisible() {
+		explorerView = overlayBottom(explorerView, m.renderWhichKey())
+	}
+
+	if m.showPreview {
+		explorerView = lipgloss.JoinHorizontal(lipgloss.Top, explorerView, m.previewModel.View())
+	}
+
 	sections = append(sections, m.renderHeader())
-	sections = append(sections, m.explorerModel.View())
+	sections = append(sections, explorerView)
 	if m.inputModel.IsVisible() {
 		sections = append(sections, m.inputModel.View())
+	} else if m.diskUsage.IsScanning() {
+		sections = append(sections, m.diskUsage.View())
 	} else if m.notificationModel.IsVisible() {
 		sections = append(sections, m.notificationModel.View())
 	}
@@ -130,35 +336,39 @@ func (m Model) View() string {
 
 	content := strings.Join(sections, "\n")
 
+	if !m.showBorder {
+		return content
+	}
+
 	return m.borderStyle.Render(content)
 }
 
 // renderHeader renders the header section
 func (m Model) renderHeader() string {
-	// Combine mode and items information
-	var modeInfo string
-	totalCount, selectedCount := m.explorerModel.GetStats()
-	currentMode := m.modeManager.GetCurrentMode()
-	if selectedCount > 0 {
-		modeInfo = fmt.Sprintf("Mode: %s | Items: %d | Selected: %d",
-			currentMode, totalCount, selectedCount)
-	} else {
-		modeInfo = fmt.Sprintf("Mode: %s | Items: %d",
-			currentMode, totalCount)
-	}
-
 	title := lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		m.titleStyle.Render(ExplorerTitle),
 		": ",
 		m.titleStyle.Render(m.currentPath),
 	)
-	mode := m.modeStyle.Render(modeInfo)
+	info := m.header.Render(m.getPlaceholderValue)
+
+	if m.compactHeader {
+		width, _ := m.explorerModel.GetSize()
 
-	return lipgloss.JoinVertical(lipgloss.Left, title, mode, "")
+		return lipgloss.NewStyle().
+			MaxWidth(width).
+			Render(lipgloss.JoinHorizontal(lipgloss.Left, title, "  ", info))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, info, "")
 }
 
 // renderFooter renders the footer section
 func (m Model) renderFooter() string {
-	return m.helpHintStyle.Render("Press " + HelpToggleKey + " for help")
+	if m.keyManager.HasPendingKeys() {
+		return m.helpHintStyle.Render("Keys: " + m.keyManager.GetPendingKeys() + " …")
+	}
+
+	return m.footer.Render(m.getPlaceholderValue)
 }
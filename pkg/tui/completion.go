@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dinhhuy258/fm/pkg/actions"
+	"github.com/dinhhuy258/fm/pkg/config"
+)
+
+// Completer provides the completion candidates of an input value
+type Completer interface {
+	// Complete returns the part of the value which is kept and the candidates which can follow it,
+	// relative paths are resolved from baseDir
+	Complete(baseDir, value string) (string, []string)
+}
+
+// newCompleter creates the completer with the given name, nil if completion is disabled
+func newCompleter(name string) Completer {
+	switch name {
+	case config.CompletionPath:
+		return pathCompleter{}
+	case config.CompletionMessage:
+		return messageCompleter{}
+	}
+
+	return nil
+}
+
+// pathCompleter completes file paths
+type pathCompleter struct{}
+
+// Complete completes the whole value as a path if it matches, e.g. a file name containing spaces,
+// otherwise the last word of the value
+func (pc pathCompleter) Complete(baseDir, value string) (string, []string) {
+	if candidates := completePath(baseDir, value); len(candidates) > 0 {
+		return "", candidates
+	}
+
+	prefix, word := splitLastWord(value)
+
+	return prefix, completePath(baseDir, word)
+}
+
+// messageCompleter completes fm messages: the message name first, then its path arguments and
+// arguments with choices
+type messageCompleter struct{}
+
+// Complete completes the message name or the argument being typed
+func (mc messageCompleter) Complete(baseDir, value string) (string, []string) {
+	prefix, word := splitLastWord(value)
+
+	words := strings.Fields(prefix)
+	if len(words) == 0 {
+		var candidates []string
+		for _, spec := range actions.GetMessageSpecs() {
+			if strings.HasPrefix(spec.Name, word) {
+				candidates = append(candidates, spec.Name)
+			}
+		}
+
+		return prefix, candidates
+	}
+
+	spec, exists := actions.LookupMessage(words[0])
+	argIndex := len(words) - 1
+
+	if !exists || argIndex >= len(spec.Args) {
+		return prefix, nil
+	}
+
+	argSpec := spec.Args[argIndex]
+	if len(argSpec.Choices) > 0 {
+		var candidates []string
+		for _, choice := range argSpec.Choices {
+			if strings.HasPrefix(choice, word) {
+				candidates = append(candidates, choice)
+			}
+		}
+
+		return prefix, candidates
+	}
+
+	if argSpec.Type != actions.ArgTypePath {
+		return prefix, nil
+	}
+
+	return prefix, completePath(baseDir, word)
+}
+
+// splitLastWord splits the value after its last space
+func splitLastWord(value string) (string, string) {
+	i := strings.LastIndexAny(value, " \t")
+
+	return value[:i+1], value[i+1:]
+}
+
+// completePath returns the paths starting with the given path, directories end with a slash.
+// Hidden files are only completed when the name starts with a dot.
+func completePath(baseDir, path string) []string {
+	dirPart, namePart := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dirPart, namePart = path[:i+1], path[i+1:]
+	}
+
+	dir := dirPart
+	if homeDir, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, "~/") {
+		dir = filepath.Join(homeDir, dir[1:])
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+
+	for _, entry := range entries {
+		name := entry.Name()
+		isHidden := strings.HasPrefix(name, ".") && !strings.HasPrefix(namePart, ".")
+
+		if isHidden || !strings.HasPrefix(name, namePart) {
+			continue
+		}
+
+		candidate := dirPart + name
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+			candidate += "/"
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	sort.Strings(candidates)
+
+	return candidates
+}
+
+// completionName returns the last segment of a candidate for display
+func completionName(candidate string) string {
+	i := strings.LastIndex(strings.TrimSuffix(candidate, "/"), "/")
+
+	return candidate[i+1:]
+}
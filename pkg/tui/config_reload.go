@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dinhhuy258/fm/pkg/actions"
+	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/config/lua"
+)
+
+const configWatchInterval = time.Second
+
+// configWatchMessage is sent periodically to check whether the config file changed
+type configWatchMessage struct{}
+
+// handleReloadConfigMessage loads the config file again on a fresh lua state.
+// The current config is kept if the new one fails to load.
+func (m Model) handleReloadConfigMessage() (tea.Model, tea.Cmd) {
+	m.configModTime = getConfigModTime()
+
+	reloadedLua := lua.NewLua()
+	if err := config.LoadConfig(reloadedLua); err != nil {
+		reloadedLua.Close()
+
+		return m, m.notificationModel.ShowNotification(NotificationError,
+			fmt.Sprintf("Failed to reload config: %v", err),
+		)
+	}
+
+	m.luaEngine.Replace(reloadedLua)
+
+	m.reloadStyles()
+	m.updateLayout()
+	m.modeManager.ReloadConfig()
+	m.keyManager.ResetPendingKeys()
+	m.history.SetSize(config.AppConfig.General.HistorySize)
+	m.directorySizes.ReloadConfig()
+
+	inputCmd := m.configureInput()
+
+	var watchCmd tea.Cmd
+	if config.AppConfig.General.WatchConfig && !m.watchingConfig {
+		m.watchingConfig = true
+		watchCmd = watchConfig()
+	}
+
+	// The problems of the config are reported instead of the success
+	if checkCmd := m.checkConfig(); checkCmd != nil {
+		return m, tea.Batch(watchCmd, inputCmd, checkCmd)
+	}
+
+	return m, tea.Batch(
+		watchCmd,
+		inputCmd,
+		m.notificationModel.ShowNotification(NotificationSuccess, "Config reloaded"),
+	)
+}
+
+// handleSetThemeMessage switches to the theme and rebuilds the styles from it
+func (m Model) handleSetThemeMessage(msg actions.SetThemeMessage) (tea.Model, tea.Cmd) {
+	if err := config.AppConfig.ApplyTheme(msg.Name); err != nil {
+		return m, m.notificationModel.ShowNotification(NotificationError,
+			fmt.Sprintf("Failed to set theme: %v", err),
+		)
+	}
+
+	m.reloadStyles()
+
+	message := "Theme removed"
+	if msg.Name != "" {
+		message = fmt.Sprintf("Theme %q applied", msg.Name)
+	}
+
+	return m, m.notificationModel.ShowNotification(NotificationSuccess, message)
+}
+
+// checkConfig validates the current config and warns about the first problem found
+func (m Model) checkConfig() tea.Cmd {
+	diagnostics := config.Validate(config.AppConfig, m.luaEngine, actions.ValidateMessage)
+	if len(diagnostics) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s (%d problem(s) in config, run fm --check-config for details)",
+		diagnostics[0], len(diagnostics))
+
+	return func() tea.Msg {
+		return actions.LogMessage{Level: actions.LogLevelWarning, Message: message}
+	}
+}
+
+// handleConfigWatchMessage reloads the config when the config file was modified
+func (m Model) handleConfigWatchMessage() (tea.Model, tea.Cmd) {
+	if !config.AppConfig.General.WatchConfig {
+		m.watchingConfig = false
+
+		return m, nil
+	}
+
+	if modTime := getConfigModTime(); !modTime.Equal(m.configModTime) {
+		model, cmd := m.handleReloadConfigMessage()
+
+		return model, tea.Batch(cmd, watchConfig())
+	}
+
+	return m, watchConfig()
+}
+
+// watchConfig schedules the next config file check
+func watchConfig() tea.Cmd {
+	return tea.Tick(configWatchInterval, func(time.Time) tea.Msg {
+		return configWatchMessage{}
+	})
+}
+
+// getConfigModTime returns the modification time of the config file,
+// the zero time is returned if there is no config file
+func getConfigModTime() time.Time {
+	configFilePath := config.GetConfigFilePath()
+	if !configFilePath.IsPresent() {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(*configFilePath.Get())
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
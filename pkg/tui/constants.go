@@ -1,7 +1,41 @@
 package tui
 
+// Layout of the main view, used to size the components and to hit-test mouse events
 const (
-	HelpToggleKey      = "?"
-	SecondaryTextColor = "#626262"
-	ExplorerTitle      = "File Explorer"
+	// borderOffsetX and borderOffsetY are the position of the content inside the border
+	borderOffsetX = 2
+	borderOffsetY = 1
+	// borderWidth and borderHeight are the space taken by the border and its padding
+	borderWidth  = 4
+	borderHeight = 2
+	// titleRow is the row of the title showing the current path
+	titleRow = 0
+	// headerHeight is the height of the header, the explorer table starts right after it.
+	// The compact header shows the title and the info line on a single line.
+	headerHeight        = 3
+	compactHeaderHeight = 1
+	// footerHeight and interactiveHeight are the heights of the footer and of the input or
+	// notification line
+	footerHeight      = 1
+	interactiveHeight = 1
+	// previewSeparatorWidth is the width of the separator on the left of the preview, the
+	// preview is hidden when it would be narrower than minPreviewWidth
+	previewSeparatorWidth = 2
+	minPreviewWidth       = 10
+)
+
+// Names of the builtin modes switched to by fm
+const (
+	defaultMode   = "default"
+	diskUsageMode = "disk-usage"
+)
+
+// updateCountFromKeyMessage is the name of the message adding the digit of its key to the count
+const updateCountFromKeyMessage = "UpdateCountFromKey"
+
+const (
+	HelpToggleKey = "?"
+	ExplorerTitle = "File Explorer"
+	// entryErrorMarker follows the name of the entries which can't be read
+	entryErrorMarker = "!"
 )
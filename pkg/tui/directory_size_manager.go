@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/fs"
+)
+
+// directorySizeSortDelay is the delay between two sorts of the entries by size while the sizes
+// arrive
+const directorySizeSortDelay = 200 * time.Millisecond
+
+// DirectorySizeManager computes the recursive sizes of the directories of the listing in the
+// background
+type DirectorySizeManager struct {
+	enabled     bool
+	concurrency int
+	sizer       *fs.DirectorySizer
+
+	// generation identifies the listing whose sizes are computed, the sizes computed for the
+	// previous listings are dropped
+	generation int
+	cancel     context.CancelFunc
+	// sortScheduled is set while a sort of the current listing is scheduled
+	sortScheduled bool
+}
+
+// NewDirectorySizeManager creates a new directory size manager
+func NewDirectorySizeManager() *DirectorySizeManager {
+	dm := &DirectorySizeManager{}
+	if directorySizeConfig := config.AppConfig.General.DirectorySize; directorySizeConfig != nil {
+		dm.enabled = directorySizeConfig.Enabled
+	}
+	dm.ReloadConfig()
+
+	return dm
+}
+
+// ReloadConfig applies the concurrency, the cached sizes are dropped when it changes
+func (dm *DirectorySizeManager) ReloadConfig() {
+	concurrency := 1
+	if directorySizeConfig := config.AppConfig.General.DirectorySize; directorySizeConfig != nil {
+		concurrency = max(directorySizeConfig.Concurrency, 1)
+	}
+
+	if dm.sizer == nil || concurrency != dm.concurrency {
+		dm.concurrency = concurrency
+		dm.sizer = fs.NewDirectorySizer(concurrency)
+	}
+}
+
+// Toggle enables or disables the computation of the sizes
+func (dm *DirectorySizeManager) Toggle() {
+	dm.enabled = !dm.enabled
+}
+
+// IsEnabled returns whether the sizes are computed
+func (dm *DirectorySizeManager) IsEnabled() bool {
+	return dm.enabled
+}
+
+// Load cancels the computation of the previous listing and returns the commands computing the
+// sizes of the directories of the entries. Symlinks to directories are skipped.
+func (dm *DirectorySizeManager) Load(entries []fs.IEntry) tea.Cmd {
+	dm.Cancel()
+
+	if !dm.enabled {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dm.cancel = cancel
+
+	generation := dm.generation
+	sizer := dm.sizer
+
+	var cmds []tea.Cmd
+
+	for _, entry := range entries {
+		if !entry.IsDirectory() || entry.IsSymlink() {
+			continue
+		}
+
+		path := entry.GetPath()
+		cmds = append(cmds, func() tea.Msg {
+			size, err := sizer.Size(ctx, path)
+
+			return directorySizeMessage{
+				generation: generation,
+				path:       path,
+				size:       size,
+				err:        err,
+			}
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// Cancel stops the computation of the sizes of the current listing
+func (dm *DirectorySizeManager) Cancel() {
+	if dm.cancel != nil {
+		dm.cancel()
+		dm.cancel = nil
+	}
+
+	dm.generation++
+	dm.sortScheduled = false
+}
+
+// ScheduleSort returns the command requesting a sort of the entries after the delay, the sizes
+// which arrive in the meantime are sorted at once. It returns nil if a sort is already scheduled.
+func (dm *DirectorySizeManager) ScheduleSort() tea.Cmd {
+	if dm.sortScheduled {
+		return nil
+	}
+
+	dm.sortScheduled = true
+	generation := dm.generation
+
+	return tea.Tick(directorySizeSortDelay, func(time.Time) tea.Msg {
+		return directorySizeSortMessage{generation: generation}
+	})
+}
+
+// EndSort returns whether the scheduled sort is for the current listing, the next sizes schedule
+// a new sort
+func (dm *DirectorySizeManager) EndSort(generation int) bool {
+	if generation != dm.generation {
+		return false
+	}
+
+	dm.sortScheduled = false
+
+	return true
+}
+
+// IsCurrent returns whether a size was computed for the current listing
+func (dm *DirectorySizeManager) IsCurrent(generation int) bool {
+	return dm.cancel != nil && generation == dm.generation
+}
+
+// ClearCache drops the cached sizes so that they are computed again
+func (dm *DirectorySizeManager) ClearCache() {
+	dm.sizer.ClearCache()
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/fs"
+)
+
+// diskUsageProgressInterval is the delay between two refreshes of the progress of a scan
+const diskUsageProgressInterval = 100 * time.Millisecond
+
+// DiskUsageModel scans the disk usage of a directory in the background and keeps the result while
+// the entries are listed by disk usage
+type DiskUsageModel struct {
+	width int
+
+	// scanID identifies the scan in progress, the results of the cancelled scans are dropped
+	scanID   int
+	scanPath string
+	cancel   context.CancelFunc
+	progress *fs.DiskUsageProgress
+
+	// root is the result of the last scan, nil when the entries are not listed by disk usage
+	root *fs.DiskUsage
+
+	// Styles
+	progressStyle lipgloss.Style
+}
+
+// NewDiskUsageModel creates a new disk usage model
+func NewDiskUsageModel() *DiskUsageModel {
+	m := &DiskUsageModel{}
+	m.ReloadConfig()
+
+	return m
+}
+
+// ReloadConfig recreates the styles which depend on the config
+func (m *DiskUsageModel) ReloadConfig() {
+	m.progressStyle = fromStyleConfig(config.AppConfig.General.InfoStyle)
+}
+
+// SetSize updates the width of the progress line
+func (m *DiskUsageModel) SetSize(width int) {
+	m.width = width
+}
+
+// Scan cancels the scan in progress and returns the commands scanning the directory and
+// refreshing its progress. The result of the previous scan is kept until the scan is done.
+func (m *DiskUsageModel) Scan(path string) tea.Cmd {
+	m.Cancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	progress := &fs.DiskUsageProgress{}
+	scanID := m.scanID
+
+	m.scanPath = path
+	m.cancel = cancel
+	m.progress = progress
+
+	return tea.Batch(
+		func() tea.Msg {
+			root, err := fs.ScanDiskUsage(ctx, path, progress)
+
+			return diskUsageScannedMessage{scanID: scanID, root: root, err: err}
+		},
+		m.Tick(scanID),
+	)
+}
+
+// Tick returns the command refreshing the progress of the scan while it runs
+func (m *DiskUsageModel) Tick(scanID int) tea.Cmd {
+	if !m.IsScanning() || scanID != m.scanID {
+		return nil
+	}
+
+	return tea.Tick(diskUsageProgressInterval, func(time.Time) tea.Msg {
+		return diskUsageProgressMessage{scanID: scanID}
+	})
+}
+
+// SetResult ends the scan with its result, it returns false if the scan was cancelled
+func (m *DiskUsageModel) SetResult(msg diskUsageScannedMessage) bool {
+	if !m.IsScanning() || msg.scanID != m.scanID {
+		return false
+	}
+
+	m.cancel()
+	m.cancel = nil
+	m.scanID++
+
+	if msg.err == nil {
+		m.root = msg.root
+	}
+
+	return true
+}
+
+// Cancel stops the scan in progress
+func (m *DiskUsageModel) Cancel() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+
+	m.scanID++
+}
+
+// Hide drops the result of the last scan, the scan in progress goes on
+func (m *DiskUsageModel) Hide() {
+	m.root = nil
+}
+
+// Exit stops the scan in progress and drops the result of the last scan
+func (m *DiskUsageModel) Exit() {
+	m.Cancel()
+	m.Hide()
+}
+
+// IsScanning returns whether a scan is in progress
+func (m *DiskUsageModel) IsScanning() bool {
+	return m.cancel != nil
+}
+
+// IsActive returns whether the entries are listed by disk usage
+func (m *DiskUsageModel) IsActive() bool {
+	return m.root != nil
+}
+
+// Find returns the disk usage of a scanned directory, nil if the directory wasn't scanned
+func (m *DiskUsageModel) Find(path string) *fs.DiskUsage {
+	if m.root == nil {
+		return nil
+	}
+
+	return m.root.Find(path)
+}
+
+// View renders the progress of the scan
+func (m *DiskUsageModel) View() string {
+	files, size := m.progress.Get()
+	text := fmt.Sprintf("Scanning %s: %d files, %s", m.scanPath, files, fs.Humanize(size))
+
+	return m.progressStyle.Render(Truncate(text, m.width, "..."))
+}
+
+// getDiskUsageSize returns the disk usage of an entry of a scanned directory. The usage of the
+// symlinks and of the directories which were not scanned is unknown.
+func getDiskUsageSize(usage *fs.DiskUsage, entry fs.IEntry) (int64, bool) {
+	switch {
+	case entry.IsSymlink() || entry.GetError() != nil:
+		return 0, false
+	case entry.IsDirectory():
+		child := usage.Children[entry.GetName()]
+		if child == nil {
+			return 0, false
+		}
+
+		return child.Size, true
+	default:
+		return entry.GetSize(), true
+	}
+}
+
+// sortByDiskUsage sets the sizes of the directories from their disk usage and sorts the entries
+// by disk usage, largest first
+func sortByDiskUsage(entries []fs.IEntry, usage *fs.DiskUsage) {
+	for _, entry := range entries {
+		directory, ok := entry.(*fs.Directory)
+		if !ok {
+			continue
+		}
+
+		if size, known := getDiskUsageSize(usage, entry); known {
+			directory.SetContentSize(size)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		size1, _ := getDiskUsageSize(usage, entries[i])
+		size2, _ := getDiskUsageSize(usage, entries[j])
+
+		return size1 > size2
+	})
+}
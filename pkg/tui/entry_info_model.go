@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/fs"
+)
+
+// entryInfoRow is a line of the entry info: a label and its value
+type entryInfoRow struct {
+	label   string
+	value   string
+	isError bool
+}
+
+// EntryInfoModel shows the information about an entry, including the error which prevented
+// reading it
+type EntryInfoModel struct {
+	width  int
+	height int
+
+	visible bool
+
+	name string
+	rows []entryInfoRow
+
+	// Styles
+	titleStyle       lipgloss.Style
+	instructionStyle lipgloss.Style
+	borderStyle      lipgloss.Style
+	labelStyle       lipgloss.Style
+	errorStyle       lipgloss.Style
+}
+
+// NewEntryInfoModel creates a new entry info model
+func NewEntryInfoModel() *EntryInfoModel {
+	m := &EntryInfoModel{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Align(lipgloss.Center),
+		labelStyle: lipgloss.NewStyle().Bold(true),
+	}
+	m.ReloadConfig()
+
+	return m
+}
+
+// ReloadConfig recreates the styles which depend on the config
+func (m *EntryInfoModel) ReloadConfig() {
+	_, selFrameColor := getFrameColors()
+
+	m.instructionStyle = fromStyleConfig(config.AppConfig.General.FooterStyle).
+		Align(lipgloss.Center)
+	m.borderStyle = newBorderStyle(selFrameColor)
+	m.errorStyle = fromStyleConfig(config.AppConfig.General.LogErrorUI.Style)
+}
+
+// SetSize updates the entry info size
+func (m *EntryInfoModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the information about the entry
+func (m *EntryInfoModel) Show(entry fs.IEntry) {
+	m.visible = true
+	m.name = entry.GetName()
+	m.rows = []entryInfoRow{
+		{label: "Path", value: entry.GetPath()},
+		{label: "Type", value: getEntryTypeName(entry)},
+	}
+
+	if entry.IsSymlink() {
+		m.rows = append(m.rows, entryInfoRow{label: "Target", value: entry.GetLinkTarget()})
+	}
+
+	if err := entry.GetError(); err != nil {
+		m.rows = append(m.rows, entryInfoRow{label: "Error", value: err.Error(), isError: true})
+
+		return
+	}
+
+	if size := formatEntrySize(entry); size != "" {
+		m.rows = append(m.rows, entryInfoRow{label: "Size", value: size})
+	}
+
+	m.rows = append(m.rows,
+		entryInfoRow{label: "Permissions", value: entry.GetPermissions()},
+		entryInfoRow{label: "Changed", value: entry.GetChangeTime().Format(time.DateTime)},
+	)
+}
+
+// Hide closes the entry info
+func (m *EntryInfoModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the entry info is visible
+func (m *EntryInfoModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles the keys of the entry info
+func (m *EntryInfoModel) Update(msg tea.KeyMsg) {
+	if !m.visible {
+		return
+	}
+
+	switch msg.String() {
+	case "esc", "q", "enter", "i":
+		m.Hide()
+	}
+}
+
+// getEntryTypeName returns the description of the type of an entry
+func getEntryTypeName(entry fs.IEntry) string {
+	switch {
+	case entry.GetError() != nil:
+		return "unknown"
+	case entry.IsBrokenSymlink():
+		return "broken symlink"
+	case entry.IsSymlink() && entry.IsDirectory():
+		return "directory symlink"
+	case entry.IsSymlink():
+		return "file symlink"
+	case entry.IsDirectory():
+		return "directory"
+	default:
+		return "file"
+	}
+}
+
+// View renders the entry info
+func (m *EntryInfoModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	labelWidth, valueWidth := 0, 0
+	for _, row := range m.rows {
+		labelWidth = max(labelWidth, lipgloss.Width(row.label))
+		valueWidth = max(valueWidth, lipgloss.Width(row.value))
+	}
+
+	// Long values are wrapped, account for border, padding and the space after the labels
+	valueStyle := lipgloss.NewStyle().Width(max(min(valueWidth, m.width-labelWidth-5), 1))
+
+	lines := make([]string, 0, len(m.rows))
+	for _, row := range m.rows {
+		label := m.labelStyle.Render(row.label + strings.Repeat(" ", labelWidth-len(row.label)+1))
+
+		value := valueStyle.Render(row.value)
+		if row.isError {
+			value = m.errorStyle.Inherit(valueStyle).Render(row.value)
+		}
+
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, label, value))
+	}
+
+	instructions := m.instructionStyle.Render("esc close")
+
+	rendered := m.borderStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Center,
+		m.titleStyle.Render(m.name),
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+		instructions,
+	))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		rendered,
+		lipgloss.WithWhitespaceChars(""),
+	)
+}
@@ -1,6 +1,11 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,6 +15,7 @@ import (
 
 	"github.com/dinhhuy258/fm/pkg/config"
 	"github.com/dinhhuy258/fm/pkg/fs"
+	"github.com/dinhhuy258/fm/pkg/types"
 )
 
 // nodeType represents an icon with its style
@@ -28,18 +34,14 @@ type nodeTypes struct {
 	fileSymlink nodeType
 	// Directory symlink icon and style
 	directorySymlink nodeType
+	// Icon and style of the symlinks whose target doesn't exist
+	brokenSymlink nodeType
 	// Maps for extensions and special files
 	extensions map[string]nodeType
 	// Special files with custom icons
 	specials map[string]nodeType
 }
 
-// headerStyles contains styles for each column header
-type headerStyles struct {
-	indexHeader lipgloss.Style
-	nameHeader  lipgloss.Style
-}
-
 // ExplorerViewData holds the computed styles and icons for rendering
 type ExplorerViewData struct {
 	// Computed styles from config
@@ -48,9 +50,11 @@ type ExplorerViewData struct {
 	focusStyle            lipgloss.Style
 	selectionStyle        lipgloss.Style
 	focusSelectionStyle   lipgloss.Style
+	rangeStyle            lipgloss.Style
+	errorStyle            lipgloss.Style
 
-	// Header styles
-	headerStyles headerStyles
+	// Header styles by column name
+	headerStyles map[string]lipgloss.Style
 
 	// Icons mapping
 	icons nodeTypes
@@ -64,6 +68,11 @@ type ExplorerModel struct {
 
 	// File system state
 	entries []fs.IEntry
+	// usage is the disk usage of the listed directory, the usage column is shown when it is set
+	usage *fs.DiskUsage
+	// showDirectorySizes is set while the directory sizes are computed, the size column is shown
+	// even if it is not configured
+	showDirectorySizes bool
 
 	// Navigation state
 	focus       int
@@ -71,11 +80,16 @@ type ExplorerModel struct {
 
 	// Selection state
 	selections set.Set[string]
+	// rangeAnchor is the index where the range selection started, noRangeAnchor if there is no range
+	rangeAnchor int
 
 	// Contains styles and icons for rendering
 	viewData *ExplorerViewData
 }
 
+// noRangeAnchor means that no range selection is in progress
+const noRangeAnchor = -1
+
 // NewExplorerModel creates a new explorer model
 func NewExplorerModel() *ExplorerModel {
 	viewData := &ExplorerViewData{}
@@ -84,6 +98,7 @@ func NewExplorerModel() *ExplorerModel {
 
 	return &ExplorerModel{
 		selections:  set.NewSet[string](),
+		rangeAnchor: noRangeAnchor,
 		focus:       0,
 		scrollStart: 0,
 		entries:     make([]fs.IEntry, 0),
@@ -91,17 +106,39 @@ func NewExplorerModel() *ExplorerModel {
 	}
 }
 
+// ReloadConfig rebuilds the styles and icons from the current config
+func (m *ExplorerModel) ReloadConfig() {
+	m.viewData.initStyles()
+	m.viewData.initIcons()
+}
+
 // SetSize updates the model dimensions
 func (m *ExplorerModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
 }
 
+// GetSize returns the model dimensions
+func (m *ExplorerModel) GetSize() (int, int) {
+	return m.width, m.height
+}
+
 // SetEntries updates the entries and resets focus/selection state
 func (m *ExplorerModel) SetEntries(entries []fs.IEntry) {
 	m.entries = entries
 	m.focus = 0
 	m.scrollStart = 0
+	m.rangeAnchor = noRangeAnchor
+}
+
+// SetDiskUsage sets the disk usage of the listed directory, nil hides the usage column
+func (m *ExplorerModel) SetDiskUsage(usage *fs.DiskUsage) {
+	m.usage = usage
+}
+
+// SetShowDirectorySizes sets whether the directory sizes are computed
+func (m *ExplorerModel) SetShowDirectorySizes(show bool) {
+	m.showDirectorySizes = show
 }
 
 // Move moves the cursor by delta positions
@@ -195,14 +232,140 @@ func (m *ExplorerModel) SelectAll() {
 	}
 }
 
-// GetSelectedPaths returns all selected paths
+// DeselectPath removes the given path from the selections
+func (m *ExplorerModel) DeselectPath(path string) {
+	m.selections.Remove(path)
+}
+
+// ClearMissingSelections removes the selected paths which no longer exist and returns their count
+func (m *ExplorerModel) ClearMissingSelections() int {
+	count := 0
+
+	for _, path := range m.selections.ToSlice() {
+		if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+			m.selections.Remove(path)
+			count++
+		}
+	}
+
+	return count
+}
+
+// InvertSelection selects the unselected entries and deselects the selected ones
+func (m *ExplorerModel) InvertSelection() {
+	for _, entry := range m.entries {
+		m.ToggleSelectionByPath(entry.GetPath())
+	}
+}
+
+// SelectMatching selects the entries matched by the matcher
+func (m *ExplorerModel) SelectMatching(matcher fs.EntryMatcher) {
+	for _, entry := range m.entries {
+		if matcher(entry) {
+			m.selections.Add(entry.GetPath())
+		}
+	}
+}
+
+// DeselectMatching deselects the entries matched by the matcher
+func (m *ExplorerModel) DeselectMatching(matcher fs.EntryMatcher) {
+	for _, entry := range m.entries {
+		if matcher(entry) {
+			m.selections.Remove(entry.GetPath())
+		}
+	}
+}
+
+// CountMatching returns the number of entries matched by the matcher
+func (m *ExplorerModel) CountMatching(matcher fs.EntryMatcher) int {
+	count := 0
+
+	for _, entry := range m.entries {
+		if matcher(entry) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// StartRange starts a range selection at the focused entry, the entries between the anchor
+// and the focus are selected until the range is committed or cancelled
+func (m *ExplorerModel) StartRange() {
+	if len(m.entries) == 0 {
+		return
+	}
+
+	m.rangeAnchor = m.focus
+}
+
+// CommitRange adds the entries of the range to the selections and ends the range
+func (m *ExplorerModel) CommitRange() {
+	for _, path := range m.getRangePaths() {
+		m.selections.Add(path)
+	}
+
+	m.rangeAnchor = noRangeAnchor
+}
+
+// CancelRange ends the range without selecting its entries
+func (m *ExplorerModel) CancelRange() {
+	m.rangeAnchor = noRangeAnchor
+}
+
+// IsRangeActive returns whether a range selection is in progress
+func (m *ExplorerModel) IsRangeActive() bool {
+	return m.rangeAnchor != noRangeAnchor
+}
+
+// isInRange checks if the entry at the given index is between the range anchor and the focus
+func (m *ExplorerModel) isInRange(idx int) bool {
+	if !m.IsRangeActive() {
+		return false
+	}
+
+	return idx >= min(m.rangeAnchor, m.focus) && idx <= max(m.rangeAnchor, m.focus)
+}
+
+// getRangePaths returns the paths of the entries in the range
+func (m *ExplorerModel) getRangePaths() []string {
+	if !m.IsRangeActive() || len(m.entries) == 0 {
+		return nil
+	}
+
+	start := min(m.rangeAnchor, m.focus)
+	end := min(max(m.rangeAnchor, m.focus), len(m.entries)-1)
+
+	paths := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		paths = append(paths, m.entries[i].GetPath())
+	}
+
+	return paths
+}
+
+// getEffectiveSelections returns the selections including the entries of the range in progress
+func (m *ExplorerModel) getEffectiveSelections() set.Set[string] {
+	if !m.IsRangeActive() {
+		return m.selections
+	}
+
+	selections := m.selections.Clone()
+	for _, path := range m.getRangePaths() {
+		selections.Add(path)
+	}
+
+	return selections
+}
+
+// GetSelectedPaths returns all selected paths, including the range in progress
 func (m *ExplorerModel) GetSelectedPaths() []string {
-	return m.selections.ToSlice()
+	return m.getEffectiveSelections().ToSlice()
 }
 
 // GetStats returns total and selected entry counts
 func (m *ExplorerModel) GetStats() (total, selected int) {
-	return len(m.entries), m.selections.Cardinality()
+	return len(m.entries), m.getEffectiveSelections().Cardinality()
 }
 
 // FocusPath attempts to focus on an entry with the given path
@@ -217,6 +380,40 @@ func (m *ExplorerModel) FocusPath(path string) {
 	}
 }
 
+// SetDirectorySize sets the recursive size of the content of a directory of the listing
+func (m *ExplorerModel) SetDirectorySize(path string, size int64) {
+	for _, entry := range m.entries {
+		if directory, ok := entry.(*fs.Directory); ok && directory.GetPath() == path {
+			directory.SetContentSize(size)
+
+			return
+		}
+	}
+}
+
+// SortEntries sorts the entries again, e.g. after their sizes changed. The focus and the range
+// anchor stay on the same entries.
+func (m *ExplorerModel) SortEntries(sortType types.SortType, reverse bool) {
+	var focusPath, anchorPath string
+	if entry := m.GetFocusedEntry(); entry != nil {
+		focusPath = entry.GetPath()
+	}
+
+	if m.IsRangeActive() && m.rangeAnchor < len(m.entries) {
+		anchorPath = m.entries[m.rangeAnchor].GetPath()
+	}
+
+	fs.SortEntries(m.entries, sortType.String(), reverse, false, false)
+
+	for i, entry := range m.entries {
+		if entry.GetPath() == anchorPath {
+			m.rangeAnchor = i
+		}
+	}
+
+	m.FocusPath(focusPath)
+}
+
 // getVisibleRows calculates how many rows can fit in the current height
 func (m *ExplorerModel) getVisibleRows() int {
 	// Reserve one row for the header
@@ -244,9 +441,18 @@ func (d *ExplorerViewData) initStyles() {
 	d.focusStyle = fromStyleConfig(explorerConfig.FocusUI.Style)
 	d.selectionStyle = fromStyleConfig(explorerConfig.SelectionUI.Style)
 	d.focusSelectionStyle = fromStyleConfig(explorerConfig.FocusSelectionUI.Style)
-	d.headerStyles = headerStyles{
-		indexHeader: fromStyleConfig(explorerConfig.IndexHeader.Style),
-		nameHeader:  fromStyleConfig(explorerConfig.NameHeader.Style),
+	d.rangeStyle = fromStyleConfig(explorerConfig.RangeUI.Style)
+	d.errorStyle = lipgloss.NewStyle()
+	d.headerStyles = make(map[string]lipgloss.Style)
+
+	for name, header := range getColumnHeaders(explorerConfig) {
+		if header != nil {
+			d.headerStyles[name] = fromStyleConfig(header.Style)
+		}
+	}
+
+	if logErrorUI := config.AppConfig.General.LogErrorUI; logErrorUI != nil {
+		d.errorStyle = fromStyleConfig(logErrorUI.Style)
 	}
 }
 
@@ -270,6 +476,10 @@ func (d *ExplorerViewData) initIcons() {
 			icon:  nodeTypesConfig.DirectorySymlink.Icon,
 			style: fromStyleConfig(nodeTypesConfig.DirectorySymlink.Style),
 		},
+		brokenSymlink: nodeType{
+			icon:  nodeTypesConfig.BrokenSymlink.Icon,
+			style: fromStyleConfig(nodeTypesConfig.BrokenSymlink.Style),
+		},
 		extensions: make(map[string]nodeType),
 		specials:   make(map[string]nodeType),
 	}
@@ -293,7 +503,11 @@ func (d *ExplorerViewData) initIcons() {
 
 // columnConfig represents column configuration
 type columnConfig struct {
+	name       string
+	title      string
 	percentage int
+	minWidth   int
+	priority   int
 	leftAlign  bool
 }
 
@@ -311,6 +525,7 @@ type entryDisplayState struct {
 	style      lipgloss.Style
 	isFocused  bool
 	isSelected bool
+	isInRange  bool
 }
 
 // View renders the explorer table view using cached view data
@@ -330,17 +545,132 @@ func (m *ExplorerModel) View() string {
 	return strings.Join(sections, "\n")
 }
 
+// Column names of the explorer table
+const (
+	columnIndex = "index"
+	columnName  = "name"
+	columnSize  = "size"
+	columnDate  = "date"
+	columnUsage = "usage"
+)
+
+// columnNames lists the columns in the order they are displayed
+var columnNames = []string{columnIndex, columnUsage, columnName, columnSize, columnDate}
+
+// directorySizeHeader is the size column shown while the directory sizes are computed if no size
+// column is configured
+var directorySizeHeader = &config.ExplorerTableHeaderConfig{
+	Name:       "size",
+	Percentage: 15,
+	MinWidth:   9,
+	Priority:   3,
+}
+
+// dateColumnFormat is the format of the date column
+const dateColumnFormat = "2006-01-02 15:04"
+
+// getColumnHeaders returns the header configs by column name, optional columns which are not
+// configured are nil
+func getColumnHeaders(
+	explorerConfig *config.ExplorerTableConfig,
+) map[string]*config.ExplorerTableHeaderConfig {
+	return map[string]*config.ExplorerTableHeaderConfig{
+		columnIndex: explorerConfig.IndexHeader,
+		columnName:  explorerConfig.NameHeader,
+		columnSize:  explorerConfig.SizeHeader,
+		columnDate:  explorerConfig.DateHeader,
+		columnUsage: explorerConfig.UsageHeader,
+	}
+}
+
+// getColumns returns the layout of the visible table columns. While the table is narrower than
+// the min widths of its columns, the column with the lowest priority is dropped.
+func (m *ExplorerModel) getColumns() []columnConfig {
+	headers := getColumnHeaders(config.AppConfig.General.ExplorerTable)
+
+	columns := make([]columnConfig, 0, len(columnNames))
+	minWidth := 0
+
+	for _, name := range columnNames {
+		header := headers[name]
+		if header == nil && name == columnSize && m.showDirectorySizes {
+			header = directorySizeHeader
+		}
+
+		if header == nil || (name == columnUsage && m.usage == nil) {
+			continue
+		}
+
+		columns = append(columns, columnConfig{
+			name:       name,
+			title:      header.Name,
+			percentage: header.Percentage,
+			minWidth:   header.MinWidth,
+			priority:   header.Priority,
+			// Sizes are aligned on the right
+			leftAlign: name != columnSize,
+		})
+		minWidth += header.MinWidth
+	}
+
+	for minWidth > m.width {
+		lowest := -1
+		for i, column := range columns {
+			if column.name != columnName && (lowest < 0 || column.priority < columns[lowest].priority) {
+				lowest = i
+			}
+		}
+
+		if lowest < 0 {
+			break
+		}
+
+		minWidth -= columns[lowest].minWidth
+		columns = slices.Delete(columns, lowest, lowest+1)
+	}
+
+	return columns
+}
+
+// GetColumnAt returns the name of the column at the given x position of the table,
+// an empty string if there is no column at this position
+func (m *ExplorerModel) GetColumnAt(x int) string {
+	columns := m.getColumns()
+
+	start := 0
+	for i, width := range m.getColumnWidths(columns) {
+		if x >= start && x < start+width {
+			return columns[i].name
+		}
+
+		start += width
+	}
+
+	return ""
+}
+
+// GetEntryIndexAtRow returns the index of the entry rendered at the given row of the table,
+// the header is row 0
+func (m *ExplorerModel) GetEntryIndexAtRow(row int) (int, bool) {
+	if row < 1 || row > m.getVisibleRows() {
+		return 0, false
+	}
+
+	index := m.scrollStart + row - 1
+	if index >= len(m.entries) {
+		return 0, false
+	}
+
+	return index, true
+}
+
 // renderHeader renders the column headers
 func (m *ExplorerModel) renderHeader() string {
-	explorerConfig := config.AppConfig.General.ExplorerTable
-	columns := []columnConfig{
-		{percentage: explorerConfig.IndexHeader.Percentage, leftAlign: true},
-		{percentage: explorerConfig.NameHeader.Percentage, leftAlign: true},
-	}
+	columns := m.getColumns()
 
-	values := []styledValue{
-		{text: explorerConfig.IndexHeader.Name, style: m.viewData.headerStyles.indexHeader},
-		{text: explorerConfig.NameHeader.Name, style: m.viewData.headerStyles.nameHeader},
+	values := make([]styledValue, len(columns))
+	for i, column := range columns {
+		values[i] = styledValue{text: column.title, style: m.viewData.headerStyles[column.name]}
 	}
 
 	return m.formatRow(columns, values)
@@ -375,7 +705,7 @@ func (m *ExplorerModel) renderEntry(entry fs.IEntry, idx int) string {
 	entryIcon := m.getEntryIcon(entry, state.isFocused, state.isSelected)
 	nameColumn := m.buildEntryDisplayName(entry, entryIcon, state)
 
-	return m.formatEntryRow(idx, nameColumn, state.style)
+	return m.formatEntryRow(entry, idx, nameColumn, state.style)
 }
 
 // determineEntryDisplayState calculates the display state for an entry based on focus/selection
@@ -383,6 +713,7 @@ func (m *ExplorerModel) determineEntryDisplayState(entry fs.IEntry, idx int) ent
 	explorerConfig := config.AppConfig.General.ExplorerTable
 	isFocused := idx == m.focus
 	isSelected := m.selections.Contains(entry.GetPath())
+	isInRange := m.isInRange(idx)
 
 	var prefix, suffix string
 	var style lipgloss.Style
@@ -396,6 +727,10 @@ func (m *ExplorerModel) determineEntryDisplayState(entry fs.IEntry, idx int) ent
 		prefix = explorerConfig.FocusUI.Prefix
 		suffix = explorerConfig.FocusUI.Suffix
 		style = m.viewData.focusStyle
+	case isInRange:
+		prefix = explorerConfig.RangeUI.Prefix
+		suffix = explorerConfig.RangeUI.Suffix
+		style = m.viewData.rangeStyle
 	case isSelected:
 		prefix = explorerConfig.SelectionUI.Prefix
 		suffix = explorerConfig.SelectionUI.Suffix
@@ -417,6 +752,7 @@ func (m *ExplorerModel) determineEntryDisplayState(entry fs.IEntry, idx int) ent
 		style:      style,
 		isFocused:  isFocused,
 		isSelected: isSelected,
+		isInRange:  isInRange,
 	}
 }
 
@@ -441,34 +777,107 @@ func (m *ExplorerModel) buildEntryDisplayName(
 ) string {
 	iconText := entryIcon.icon
 	fileName := strings.TrimSpace(entry.GetName())
+	if entry.IsBrokenSymlink() {
+		fileName += " -> " + entry.GetLinkTarget()
+	}
 
 	// Apply styling to just the icon if needed (but keep it simple)
-	var styledIcon string
-	if state.isFocused || state.isSelected {
+	var styledIcon, errorMarker string
+	if state.isFocused || state.isSelected || state.isInRange {
 		// For focused/selected items, apply same style to icon as text
 		styledIcon = iconText
+		errorMarker = entryErrorMarker
 	} else {
 		// For normal items, use icon's default style
 		styledIcon = entryIcon.style.Render(iconText)
+		errorMarker = m.viewData.errorStyle.Render(entryErrorMarker)
+	}
+
+	if entry.GetError() != nil {
+		fileName += " " + errorMarker
 	}
 
 	return state.treePrefix + state.prefix + styledIcon + " " + fileName + state.suffix
 }
 
-// formatEntryRow formats the complete row with index and name columns
-func (m *ExplorerModel) formatEntryRow(idx int, nameColumn string, entryStyle lipgloss.Style) string {
-	explorerConfig := config.AppConfig.General.ExplorerTable
-	columns := []columnConfig{
-		{percentage: explorerConfig.IndexHeader.Percentage, leftAlign: true},
-		{percentage: explorerConfig.NameHeader.Percentage, leftAlign: true},
+// formatEntryRow formats the complete row with the visible columns
+func (m *ExplorerModel) formatEntryRow(
+	entry fs.IEntry,
+	idx int,
+	nameColumn string,
+	entryStyle lipgloss.Style,
+) string {
+	columns := m.getColumns()
+	widths := m.getColumnWidths(columns)
+
+	values := make([]styledValue, len(columns))
+	for i, column := range columns {
+		switch column.name {
+		case columnIndex:
+			values[i] = styledValue{text: strconv.Itoa(idx + 1)}
+		case columnName:
+			values[i] = styledValue{text: nameColumn, style: entryStyle}
+		case columnSize:
+			values[i] = styledValue{text: formatEntrySize(entry)}
+		case columnDate:
+			values[i] = styledValue{text: formatEntryDate(entry)}
+		case columnUsage:
+			values[i] = styledValue{text: m.formatEntryUsage(entry, widths[i])}
+		}
+	}
+
+	return m.formatRow(columns, values)
+}
+
+// formatEntrySize returns the size shown in the size column, directories have no size until
+// their recursive size is computed and unreadable entries have no size
+func formatEntrySize(entry fs.IEntry) string {
+	if directory, ok := entry.(*fs.Directory); ok {
+		if size, hasSize := directory.GetContentSize(); hasSize {
+			return fs.Humanize(size)
+		}
 	}
 
-	values := []styledValue{
-		{text: strconv.Itoa(idx + 1)},
-		{text: nameColumn, style: entryStyle},
+	if entry.IsDirectory() || entry.GetError() != nil {
+		return ""
 	}
 
-	return m.formatRow(columns, values)
+	return fs.Humanize(entry.GetSize())
+}
+
+// formatEntryUsage returns the disk usage of the entry, its share of the usage of the listed
+// directory and a bar filling the rest of the column
+func (m *ExplorerModel) formatEntryUsage(entry fs.IEntry, width int) string {
+	size, known := getDiskUsageSize(m.usage, entry)
+	if !known {
+		return ""
+	}
+
+	ratio := 0.0
+	if m.usage.Size > 0 {
+		ratio = min(float64(size)/float64(m.usage.Size), 1)
+	}
+
+	text := fmt.Sprintf("%6s %5.1f%% ", fs.Humanize(size), ratio*100)
+
+	// Keep a space between the bar and the next column
+	barWidth := width - len(text) - len("[] ")
+	if barWidth <= 0 {
+		return text
+	}
+
+	filled := int(math.Round(ratio * float64(barWidth)))
+
+	return text + "[" + strings.Repeat("#", filled) + strings.Repeat(" ", barWidth-filled) + "]"
+}
+
+// formatEntryDate returns the date shown in the date column, unreadable entries have no date
+func formatEntryDate(entry fs.IEntry) string {
+	if entry.GetError() != nil {
+		return ""
+	}
+
+	return entry.GetChangeTime().Format(dateColumnFormat)
 }
 
 // getEntryIcon returns the appropriate icon for an entry with state-based styling
@@ -480,6 +889,8 @@ func (m *ExplorerModel) getEntryIcon(entry fs.IEntry, isEntryFocused, isEntrySel
 	specialIcon, hasSpecialIcon := m.viewData.icons.specials[strings.ToLower(entry.GetName())]
 
 	switch {
+	case entry.IsBrokenSymlink():
+		icon = m.viewData.icons.brokenSymlink
 	case entry.IsSymlink() && entry.IsDirectory():
 		icon = m.viewData.icons.directorySymlink
 	case entry.IsSymlink():
@@ -516,9 +927,35 @@ func (m *ExplorerModel) formatRow(columns []columnConfig, values []styledValue)
 	}
 
 	result := ""
+	for i, columnWidth := range m.getColumnWidths(columns) {
+		result += m.formatColumn(values[i], columnWidth, columns[i].leftAlign)
+	}
+
+	// Ensure the row doesn't exceed terminal width, the row contains escape sequences of styles
+	if lipgloss.Width(result) > m.width {
+		result = lipgloss.NewStyle().MaxWidth(m.width).Render(result)
+	}
+
+	return result
+}
+
+// getColumnWidths computes the width of the columns from their share of the percentages of the
+// visible columns. Columns narrower than their min width are widened at the expense of the name
+// column.
+func (m *ExplorerModel) getColumnWidths(columns []columnConfig) []int {
+	widths := make([]int, len(columns))
 	accumulatedColumnWidth := 0
+
+	totalPercentage := 0
+	for _, col := range columns {
+		totalPercentage += col.percentage
+	}
+
 	for i, col := range columns {
-		columnWidth := int(float32(col.percentage) / 100.0 * float32(m.width))
+		columnWidth := 0
+		if totalPercentage > 0 {
+			columnWidth = col.percentage * m.width / totalPercentage
+		}
 		// Give remaining width to the last column to avoid rounding errors
 		// that could leave empty space or cause overflow
 		if i == len(columns)-1 {
@@ -526,18 +963,29 @@ func (m *ExplorerModel) formatRow(columns []columnConfig, values []styledValue)
 		} else {
 			accumulatedColumnWidth += columnWidth
 		}
-		result += m.formatColumn(values[i], columnWidth, col.leftAlign)
+
+		widths[i] = columnWidth
+	}
+
+	nameIndex := slices.IndexFunc(columns, func(col columnConfig) bool {
+		return col.name == columnName
+	})
+	if nameIndex < 0 {
+		return widths
 	}
 
-	// Ensure the row doesn't exceed terminal width
-	if uniseg.StringWidth(result) > m.width {
-		runes := []rune(result)
-		if len(runes) > m.width {
-			result = string(runes[:m.width])
+	for i, col := range columns {
+		if i == nameIndex || widths[i] >= col.minWidth {
+			continue
 		}
+
+		spare := max(widths[nameIndex]-columns[nameIndex].minWidth, 0)
+		extra := min(col.minWidth-widths[i], spare)
+		widths[i] += extra
+		widths[nameIndex] -= extra
 	}
 
-	return result
+	return widths
 }
 
 // formatColumn formats a single column with proper alignment
@@ -546,6 +994,13 @@ func (m *ExplorerModel) formatColumn(value styledValue, columnWidth int, leftAli
 		return ""
 	}
 
+	// Keep a space between a right aligned column and the next one
+	gap := ""
+	if !leftAlign && columnWidth > 1 {
+		gap = " "
+		columnWidth--
+	}
+
 	// Truncate the string if it's wider than the column.
 	// We truncate the original string, then apply styling.
 	text := Truncate(value.text, columnWidth, "...")
@@ -562,5 +1017,5 @@ func (m *ExplorerModel) formatColumn(value styledValue, columnWidth int, leftAli
 		return styledText + strings.Repeat(" ", padding)
 	}
 
-	return strings.Repeat(" ", padding) + styledText
+	return strings.Repeat(" ", padding) + styledText + gap
 }
\ No newline at end of file
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"maps"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -9,6 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/dinhhuy258/fm/pkg/actions"
 	"github.com/dinhhuy258/fm/pkg/config"
 )
 
@@ -27,14 +31,10 @@ type keyMapEntry struct {
 	description string
 }
 
-// modeHelp represents help information for a mode
-type modeHelp struct {
-	name        string
-	keymaps     []keyMapEntry
-	hasDefault  bool
-	defaultHelp string
-	hasNumber   bool
-	numberHelp  string
+// helpSection is a titled group of key mappings
+type helpSection struct {
+	title   string
+	entries []keyMapEntry
 }
 
 // HelpModel represents the pure state for the help interface
@@ -48,7 +48,9 @@ type HelpModel struct {
 
 	// Content and viewport state
 	viewport viewport.Model
-	content  string
+	sections []helpSection
+	// query filters the key mappings by key or description
+	query string
 
 	// Configuration and mode management
 	modesConfig *config.ModesConfig
@@ -58,34 +60,47 @@ type HelpModel struct {
 	titleStyle       lipgloss.Style
 	instructionStyle lipgloss.Style
 	borderStyle      lipgloss.Style
+	sectionStyle     lipgloss.Style
+	keyStyle         lipgloss.Style
 }
 
 // NewHelpModel creates a new help model
 func NewHelpModel(modeManager *ModeManager) *HelpModel {
 	vp := viewport.New(0, 0)
 
-	// Initialize styles once
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Align(lipgloss.Center)
+	m := &HelpModel{
+		viewport:    vp,
+		visible:     false,
+		modesConfig: config.AppConfig.Modes,
+		modeManager: modeManager,
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Align(lipgloss.Center),
+		sectionStyle: lipgloss.NewStyle().Bold(true).Underline(true),
+		keyStyle:     lipgloss.NewStyle().Bold(true),
+	}
+	m.initStyles()
+
+	return m
+}
 
-	instructionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(SecondaryTextColor)).
+// initStyles creates the styles which depend on the config
+func (m *HelpModel) initStyles() {
+	_, selFrameColor := getFrameColors()
+
+	m.instructionStyle = fromStyleConfig(config.AppConfig.General.FooterStyle).
 		Align(lipgloss.Center)
+	m.borderStyle = newBorderStyle(selFrameColor)
+}
+
+// ReloadConfig reloads the modes config and the styles, and refreshes the help content if it
+// is visible
+func (m *HelpModel) ReloadConfig() {
+	m.modesConfig = config.AppConfig.Modes
+	m.initStyles()
 
-	borderStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		PaddingLeft(1).
-		PaddingRight(1)
-
-	return &HelpModel{
-		viewport:         vp,
-		visible:          false,
-		modesConfig:      config.AppConfig.Modes,
-		modeManager:      modeManager,
-		titleStyle:       titleStyle,
-		instructionStyle: instructionStyle,
-		borderStyle:      borderStyle,
+	if m.visible {
+		m.generateContent()
 	}
 }
 
@@ -96,7 +111,7 @@ func (m *HelpModel) SetSize(width, height int) {
 
 	// Account for border and padding
 	innerWidth := width - 4   // border + padding
-	innerHeight := height - 6 // border + padding + title
+	innerHeight := height - 7 // border + padding + title + filter + instructions
 
 	if innerWidth < 0 {
 		innerWidth = 0
@@ -117,6 +132,7 @@ func (m *HelpModel) GetSize() (int, int) {
 // Show displays the help UI
 func (m *HelpModel) Show() {
 	m.visible = true
+	m.query = ""
 	m.generateContent()
 }
 
@@ -125,120 +141,220 @@ func (m *HelpModel) IsVisible() bool {
 	return m.visible
 }
 
-// generateContent generates the help content for the current mode only
+// generateContent collects the key mappings of the current mode first, then the bindings
+// declared by the other modes and the reference of the messages
 func (m *HelpModel) generateContent() {
 	currentMode := m.modeManager.GetCurrentMode()
-	modeConfig := m.modeManager.GetModeConfig(currentMode)
 
-	if modeConfig == nil {
-		m.content = "No configuration found for mode: " + currentMode
-		m.viewport.SetContent(m.content)
+	m.sections = m.sections[:0]
 
-		return
+	if keyBindings := m.modeManager.GetKeyBindings(currentMode); keyBindings != nil {
+		// The current mode includes inherited and global key bindings
+		m.sections = append(m.sections, helpSection{
+			title:   "Mode: " + currentMode + " (current)",
+			entries: extractKeyMaps(keyBindings),
+		})
+	}
+
+	for _, name := range m.getModeNames() {
+		modeConfig := m.modesConfig.GetMode(name)
+		if name == currentMode || modeConfig == nil {
+			continue
+		}
+
+		title := "Mode: " + name
+		if modeConfig.Extends != "" {
+			title += " (extends " + modeConfig.Extends + ")"
+		}
+
+		m.sections = append(m.sections, helpSection{
+			title:   title,
+			entries: extractKeyMaps(&modeConfig.KeyBindings),
+		})
 	}
 
-	// Generate help for the current mode only
-	modeHelpInfo := m.extractModeHelp(currentMode, &modeConfig.KeyBindings)
+	m.sections = append(m.sections, m.extractMessagesHelp())
+
+	m.renderContent()
+}
 
-	// Create a simple header and format the help
-	var sections []string
-	sections = append(sections, m.formatModeHelp(modeHelpInfo))
+// getModeNames returns the names of the custom and builtin modes, sorted
+func (m *HelpModel) getModeNames() []string {
+	names := slices.Collect(maps.Keys(m.modesConfig.Customs))
+	names = append(names, slices.Collect(maps.Keys(m.modesConfig.Builtins))...)
+	slices.Sort(names)
 
-	m.content = strings.Join(sections, "\n\n")
-	m.viewport.SetContent(m.content)
+	return slices.Compact(names)
 }
 
-// extractModeHelp extracts help information from a mode's key bindings
-func (m *HelpModel) extractModeHelp(
-	modeName string,
-	keyBindings *config.KeyBindingsConfig,
-) modeHelp {
-	modeHelpInfo := modeHelp{
-		name:    modeName,
-		keymaps: []keyMapEntry{},
-	}
+// extractKeyMaps extracts the key mappings of key bindings, sorted by key. Bindings without
+// help are described by the names of their messages.
+func extractKeyMaps(keyBindings *config.KeyBindingsConfig) []keyMapEntry {
+	keymaps := make([]keyMapEntry, 0, len(keyBindings.OnKeys))
 
-	if keyBindings.OnKeys != nil {
-		var keys []string
-		for k := range keyBindings.OnKeys {
-			keys = append(keys, k)
+	for k, actionConfig := range keyBindings.OnKeys {
+		if actionConfig == nil {
+			continue
 		}
-		sort.Strings(keys)
-
-		for _, k := range keys {
-			actionConfig := keyBindings.OnKeys[k]
-			if actionConfig.Help != "" {
-				modeHelpInfo.keymaps = append(modeHelpInfo.keymaps, keyMapEntry{
-					key:         k,
-					description: actionConfig.Help,
-				})
-			}
+
+		keymaps = append(keymaps, keyMapEntry{
+			// Key sequences are displayed with their keys separated by spaces, e.g. "g g"
+			key:         config.FormatKeySequence(config.ParseKeySequence(k)),
+			description: describeAction(actionConfig),
+		})
+	}
+
+	sort.Slice(keymaps, func(i, j int) bool {
+		return keymaps[i].key < keymaps[j].key
+	})
+
+	if keyBindings.Default != nil {
+		keymaps = append(keymaps, keyMapEntry{
+			key:         "<other>",
+			description: describeAction(keyBindings.Default),
+		})
+	}
+
+	if keyBindings.OnNumber != nil {
+		keymaps = append(keymaps, keyMapEntry{
+			key:         "<number>",
+			description: describeAction(keyBindings.OnNumber),
+		})
+	}
+
+	for _, event := range config.MouseEvents {
+		if actionConfig := keyBindings.OnMouse[event]; actionConfig != nil {
+			keymaps = append(keymaps, keyMapEntry{
+				key:         "<" + event + ">",
+				description: describeAction(actionConfig),
+			})
 		}
 	}
 
-	if keyBindings.Default != nil && keyBindings.Default.Help != "" {
-		modeHelpInfo.hasDefault = true
-		modeHelpInfo.defaultHelp = keyBindings.Default.Help
+	return keymaps
+}
+
+// describeAction returns the help of the action, or the names of its messages if it has no help
+func describeAction(actionConfig *config.ActionConfig) string {
+	if actionConfig.Help != "" {
+		return actionConfig.Help
 	}
 
-	if keyBindings.OnNumber != nil && keyBindings.OnNumber.Help != "" {
-		modeHelpInfo.hasNumber = true
-		modeHelpInfo.numberHelp = keyBindings.OnNumber.Help
+	names := make([]string, 0, len(actionConfig.Messages))
+	for _, message := range actionConfig.Messages {
+		if message != nil {
+			names = append(names, message.Name)
+		}
 	}
 
-	return modeHelpInfo
+	return strings.Join(names, ", ")
 }
 
-// formatModeHelp formats help information for a single mode
-func (m *HelpModel) formatModeHelp(modeHelpInfo modeHelp) string {
-	var lines []string
+// extractMessagesHelp returns the reference of all messages supported by fm
+func (m *HelpModel) extractMessagesHelp() helpSection {
+	section := helpSection{title: "Messages"}
+
+	for _, spec := range actions.GetMessageSpecs() {
+		section.entries = append(section.entries, keyMapEntry{
+			key:         spec.Usage(),
+			description: spec.Help,
+		})
+	}
+
+	return section
+}
+
+// renderContent renders the sections filtered by the query into the viewport
+func (m *HelpModel) renderContent() {
+	query := strings.ToLower(m.query)
+
+	var blocks []string
+
+	for _, section := range m.sections {
+		var entries []keyMapEntry
+
+		for _, entry := range section.entries {
+			if strings.Contains(strings.ToLower(entry.key), query) ||
+				strings.Contains(strings.ToLower(entry.description), query) {
+				entries = append(entries, entry)
+			}
+		}
 
-	// Key bindings
-	if len(modeHelpInfo.keymaps) > 0 {
-		for _, km := range modeHelpInfo.keymaps {
-			line := km.key + " " + km.description
-			lines = append(lines, line)
+		if len(entries) > 0 {
+			blocks = append(blocks, m.formatSection(section.title, entries))
 		}
 	}
 
-	// Default action
-	if modeHelpInfo.hasDefault {
-		line := "  " + "<other>" + " " + modeHelpInfo.defaultHelp
-		lines = append(lines, line)
+	content := "No key binding matches " + strconv.Quote(m.query)
+	if len(blocks) > 0 {
+		content = strings.Join(blocks, "\n\n")
 	}
 
-	// Number action
-	if modeHelpInfo.hasNumber {
-		line := "  " + "<number>" + " " + modeHelpInfo.numberHelp
-		lines = append(lines, line)
+	m.viewport.SetContent(content)
+	m.viewport.GotoTop()
+}
+
+// formatSection formats a section with its keys aligned
+func (m *HelpModel) formatSection(title string, entries []keyMapEntry) string {
+	keyWidth := 0
+	for _, entry := range entries {
+		keyWidth = max(keyWidth, lipgloss.Width(entry.key))
+	}
+
+	lines := []string{m.sectionStyle.Render(title)}
+	for _, entry := range entries {
+		padding := strings.Repeat(" ", keyWidth-lipgloss.Width(entry.key))
+		lines = append(lines, "  "+m.keyStyle.Render(entry.key)+padding+"  "+entry.description)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-// Update handles help model updates and key events
+// Update handles help model updates and key events. Typed characters filter the key mappings.
 func (m *HelpModel) Update(msg tea.Msg) {
 	if !m.visible {
 		return
 	}
 
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case msg.Type == tea.KeyEsc:
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		// The first esc clears the filter
+		if m.query == "" {
 			m.visible = false
-		case msg.String() == "k" || msg.String() == "up":
-			m.viewport.ScrollUp(1)
-		case msg.String() == "j" || msg.String() == "down":
-			m.viewport.ScrollDown(1)
-		case msg.String() == "ctrl+u":
-			m.viewport.HalfPageUp()
-		case msg.String() == "ctrl+d":
-			m.viewport.HalfPageDown()
+		} else {
+			m.setQuery("")
+		}
+	case tea.KeyUp:
+		m.viewport.ScrollUp(1)
+	case tea.KeyDown:
+		m.viewport.ScrollDown(1)
+	case tea.KeyPgUp, tea.KeyCtrlU:
+		m.viewport.HalfPageUp()
+	case tea.KeyPgDown, tea.KeyCtrlD:
+		m.viewport.HalfPageDown()
+	case tea.KeyBackspace:
+		if m.query != "" {
+			runes := []rune(m.query)
+			m.setQuery(string(runes[:len(runes)-1]))
 		}
+	case tea.KeyCtrlW:
+		m.setQuery("")
+	case tea.KeyRunes, tea.KeySpace:
+		m.setQuery(m.query + string(keyMsg.Runes))
 	}
 }
 
+// setQuery changes the filter and renders the matching key mappings
+func (m *HelpModel) setQuery(query string) {
+	m.query = query
+	m.renderContent()
+}
+
 // View renders the help UI view
 func (m *HelpModel) View() string {
 	if !m.visible {
@@ -250,18 +366,24 @@ func (m *HelpModel) View() string {
 	// Title
 	title := m.titleStyle.Render("Help")
 
+	filter := m.instructionStyle.Render("Type to filter")
+	if m.query != "" {
+		filter = "Filter: " + m.query
+	}
+
 	// Help content with scrollable viewport
 	content := m.viewport.View()
 
 	// Instructions at the bottom
 	instructions := m.instructionStyle.Render(
-		"Press esc to close • ↑↓ to scroll",
+		"esc clear filter or close • ↑↓ to scroll",
 	)
 
 	// Combine all parts
 	helpContent := lipgloss.JoinVertical(
 		lipgloss.Center,
 		title,
+		filter,
 		content,
 		instructions,
 	)
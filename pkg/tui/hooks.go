@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dinhhuy258/fm/pkg/actions"
+	"github.com/dinhhuy258/fm/pkg/config"
+)
+
+// runStateHooks fires the directory and focus events for the changes made since the given state
+func (m Model) runStateHooks(previousPath, previousFocusPath string) tea.Cmd {
+	var cmds []tea.Cmd
+
+	if m.currentPath != previousPath {
+		cmds = append(cmds, m.runHooks(config.EventDirectoryChanged, map[string]string{
+			"path":          m.currentPath,
+			"previous_path": previousPath,
+		}))
+	}
+
+	if focusPath := m.getFocusPath(); focusPath != previousFocusPath {
+		cmds = append(cmds, m.runHooks(config.EventFocusChanged, map[string]string{
+			"path":          focusPath,
+			"previous_path": previousFocusPath,
+			"index":         strconv.Itoa(m.explorerModel.GetFocus()),
+			"directory":     m.currentPath,
+		}))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// runHooks calls the lua callbacks registered for the event and executes the messages they return.
+// Hook errors are reported as error notifications.
+func (m Model) runHooks(event string, data map[string]string) tea.Cmd {
+	messages, err := config.RunHooks(m.luaEngine, event, data)
+
+	var cmds []tea.Cmd
+	if len(messages) > 0 {
+		cmds = append(cmds, m.actionHandler.ExecuteMessages(messages, tea.KeyMsg{}))
+	}
+
+	if err != nil {
+		cmds = append(cmds, func() tea.Msg {
+			return actions.LogMessage{Level: actions.LogLevelError, Message: err.Error()}
+		})
+	}
+
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	return tea.Sequence(cmds...)
+}
+
+// getFocusPath returns the path of the focused entry or an empty string if there is none
+func (m Model) getFocusPath() string {
+	if focusedEntry := m.explorerModel.GetFocusedEntry(); focusedEntry != nil {
+		return focusedEntry.GetPath()
+	}
+
+	return ""
+}
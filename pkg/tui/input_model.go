@@ -1,11 +1,48 @@
 package tui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
 )
 
-const inputPrompt = "> "
+const (
+	inputPrompt        = "> "
+	inputValidMarker   = "✓"
+	inputInvalidMarker = "✗"
+	// completionPageSize is the number of completion candidates shown at once
+	completionPageSize = 8
+)
+
+// InputStyles holds cached styles for the validation result and the completion of the input
+type InputStyles struct {
+	validStyle     lipgloss.Style
+	invalidStyle   lipgloss.Style
+	candidateStyle lipgloss.Style
+	previewStyle   lipgloss.Style
+}
+
+// InputPreviewer describes the effect of the input value, e.g. the number of matched entries
+type InputPreviewer func(value string) string
+
+// historySearch holds the state of a reverse search in the input history
+type historySearch struct {
+	query string
+	// index is the index of the matched history entry, -1 if nothing matches
+	index int
+}
+
+// completionState holds the candidates being cycled through with tab
+type completionState struct {
+	prefix     string
+	candidates []string
+	index      int
+}
 
 // InputModel handles text input
 type InputModel struct {
@@ -14,6 +51,31 @@ type InputModel struct {
 
 	textInput textinput.Model
 	isVisible bool
+
+	// validator checks the value of the input, nil means no validation
+	validator         config.InputValidator
+	validationMessage string
+
+	// previewer describes the effect of the value, nil means no preview
+	previewer      InputPreviewer
+	previewMessage string
+
+	// completer provides the candidates completed with tab, nil means no completion
+	completer  Completer
+	completion *completionState
+	// baseDir is the directory relative paths are completed from
+	baseDir string
+
+	// history contains the previous values of the mode from the oldest to the newest
+	historyEnabled bool
+	history        []string
+	// historyIndex is the recalled history entry, len(history) means the value being typed
+	historyIndex int
+	draft        string
+	search       *historySearch
+	prompt       string
+
+	styles *InputStyles
 }
 
 // NewInputModel creates a new input model
@@ -25,21 +87,82 @@ func NewInputModel() *InputModel {
 	return &InputModel{
 		textInput: ti,
 		isVisible: false, // Default to hidden
+		prompt:    inputPrompt,
+		styles:    newInputStyles(),
 	}
 }
 
+// newInputStyles creates the input styles from the current config
+func newInputStyles() *InputStyles {
+	return &InputStyles{
+		validStyle:     fromStyleConfig(config.AppConfig.General.LogInfoUI.Style),
+		invalidStyle:   fromStyleConfig(config.AppConfig.General.LogErrorUI.Style),
+		candidateStyle: fromStyleConfig(config.AppConfig.General.ExplorerTable.FocusUI.Style),
+		previewStyle:   fromStyleConfig(config.AppConfig.General.LogInfoUI.Style),
+	}
+}
+
+// ReloadConfig recreates the styles from the current config
+func (m *InputModel) ReloadConfig() {
+	m.styles = newInputStyles()
+}
+
+// Configure applies the input config of a mode, a nil config restores the default input
+func (m *InputModel) Configure(
+	inputConfig *config.InputConfig,
+	validator config.InputValidator,
+	previewer InputPreviewer,
+) {
+	m.prompt = inputPrompt
+	m.textInput.Placeholder = ""
+	m.textInput.CharLimit = 0
+	m.validator = validator
+	m.previewer = previewer
+	m.completer = nil
+	m.completion = nil
+	m.historyEnabled = false
+	m.search = nil
+
+	if inputConfig != nil {
+		if inputConfig.Prompt != "" {
+			m.prompt = inputConfig.Prompt
+		}
+
+		m.textInput.Placeholder = inputConfig.Placeholder
+		m.textInput.CharLimit = max(inputConfig.CharLimit, 0)
+		m.completer = newCompleter(inputConfig.Completion)
+		m.historyEnabled = inputConfig.History
+	}
+
+	m.textInput.Prompt = m.prompt
+
+	m.validate()
+}
+
 // SetSize updates the model dimensions
 func (m *InputModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
 }
 
-// Show makes the input visible and focuses it
-func (m *InputModel) Show(initialValue string) {
+// IsHistoryEnabled returns whether the values of the input are recorded in the history
+func (m *InputModel) IsHistoryEnabled() bool {
+	return m.historyEnabled
+}
+
+// Show makes the input visible and focuses it.
+// Relative paths are completed from baseDir, history contains the previous values of the mode.
+func (m *InputModel) Show(initialValue string, baseDir string, history []string) {
 	m.isVisible = true
+	m.baseDir = baseDir
+	m.completion = nil
+	m.history = history
+	m.historyIndex = len(history)
+	m.endSearch()
 	m.textInput.SetValue(initialValue)
 	m.textInput.SetCursor(len(initialValue))
 	m.textInput.Focus()
+	m.validate()
 }
 
 // Hide makes the input invisible and clears it
@@ -47,6 +170,10 @@ func (m *InputModel) Hide() {
 	m.isVisible = false
 	m.textInput.Blur()
 	m.textInput.SetValue("")
+	m.validationMessage = ""
+	m.previewMessage = ""
+	m.completion = nil
+	m.endSearch()
 }
 
 // IsVisible returns whether the input is currently visible
@@ -65,17 +192,235 @@ func (m *InputModel) Update(msg tea.Msg) tea.Cmd {
 		return nil
 	}
 
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.completer != nil {
+		switch keyMsg.Type {
+		case tea.KeyTab:
+			m.complete(1)
+
+			return nil
+		case tea.KeyShiftTab:
+			m.complete(-1)
+
+			return nil
+		default:
+			m.completion = nil
+		}
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.historyEnabled && m.updateHistory(keyMsg) {
+		return nil
+	}
+
 	var cmd tea.Cmd
 	m.textInput, cmd = m.textInput.Update(msg)
+	m.validate()
 
 	return cmd
 }
 
+// updateHistory handles the history keys: up and down recall entries, ctrl+r searches backwards.
+// It returns true if the key was handled.
+func (m *InputModel) updateHistory(msg tea.KeyMsg) bool {
+	if m.search != nil {
+		switch msg.Type {
+		case tea.KeyCtrlR:
+			m.searchHistory(m.search.index - 1)
+		case tea.KeyBackspace:
+			m.search.query = trimLastRune(m.search.query)
+			m.searchHistory(len(m.history) - 1)
+		case tea.KeyRunes, tea.KeySpace:
+			m.search.query += string(msg.Runes)
+			m.searchHistory(m.search.index)
+		default:
+			// Any other key accepts the match and is handled as usual
+			m.endSearch()
+
+			return false
+		}
+
+		return true
+	}
+
+	switch msg.Type {
+	case tea.KeyUp:
+		m.recallHistory(m.historyIndex - 1)
+	case tea.KeyDown:
+		m.recallHistory(m.historyIndex + 1)
+	case tea.KeyCtrlR:
+		m.search = &historySearch{index: len(m.history)}
+		m.searchHistory(len(m.history) - 1)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// recallHistory shows the history entry at the given index, the value being typed is restored
+// after the newest entry
+func (m *InputModel) recallHistory(index int) {
+	if index < 0 || index > len(m.history) || index == m.historyIndex {
+		return
+	}
+
+	if m.historyIndex == len(m.history) {
+		m.draft = m.textInput.Value()
+	}
+
+	m.historyIndex = index
+	if index == len(m.history) {
+		m.setValue(m.draft)
+
+		return
+	}
+
+	m.setValue(m.history[index])
+}
+
+// searchHistory finds the newest entry containing the query, starting at the given index
+func (m *InputModel) searchHistory(start int) {
+	m.search.index = -1
+
+	for i := min(start, len(m.history)-1); i >= 0; i-- {
+		if strings.Contains(m.history[i], m.search.query) {
+			m.search.index = i
+			m.historyIndex = i
+			m.setValue(m.history[i])
+
+			break
+		}
+	}
+
+	status := "reverse-i-search"
+	if m.search.index < 0 {
+		status = "failed reverse-i-search"
+	}
+
+	m.textInput.Prompt = fmt.Sprintf("(%s)`%s': ", status, m.search.query)
+}
+
+// endSearch stops the reverse search and restores the prompt
+func (m *InputModel) endSearch() {
+	m.search = nil
+	m.textInput.Prompt = m.prompt
+}
+
+// trimLastRune removes the last character of the string
+func trimLastRune(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+
+	return string(runes[:len(runes)-1])
+}
+
+// complete moves to the next (direction 1) or previous (direction -1) completion candidate.
+// A single candidate is accepted right away so that the next tab completes from it.
+func (m *InputModel) complete(direction int) {
+	if m.completion == nil {
+		prefix, candidates := m.completer.Complete(m.baseDir, m.textInput.Value())
+		if len(candidates) == 0 {
+			return
+		}
+
+		if len(candidates) == 1 {
+			m.setValue(prefix + candidates[0])
+
+			return
+		}
+
+		index := 0
+		if direction < 0 {
+			index = len(candidates) - 1
+		}
+
+		m.completion = &completionState{prefix: prefix, candidates: candidates, index: index}
+	} else {
+		count := len(m.completion.candidates)
+		m.completion.index = (m.completion.index + direction + count) % count
+	}
+
+	m.setValue(m.completion.prefix + m.completion.candidates[m.completion.index])
+}
+
+// setValue replaces the value and moves the cursor to the end
+func (m *InputModel) setValue(value string) {
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+	m.validate()
+}
+
+// validate checks the current value with the validator of the mode and updates the preview
+func (m *InputModel) validate() {
+	m.validationMessage = ""
+	m.previewMessage = ""
+
+	if !m.isVisible {
+		return
+	}
+
+	if m.validator != nil {
+		m.validationMessage = m.validator(m.textInput.Value())
+	}
+
+	if m.previewer != nil {
+		m.previewMessage = m.previewer(m.textInput.Value())
+	}
+}
+
 // View renders the input view
 func (m *InputModel) View() string {
 	if !m.isVisible {
 		return ""
 	}
 
-	return m.textInput.View()
+	sections := []string{m.textInput.View()}
+
+	if m.validator != nil {
+		result := m.styles.validStyle.Render(inputValidMarker)
+		if m.validationMessage != "" {
+			result = m.styles.invalidStyle.Render(inputInvalidMarker + " " + m.validationMessage)
+		}
+
+		sections = append(sections, result)
+	}
+
+	if m.previewMessage != "" {
+		sections = append(sections, m.styles.previewStyle.Render(m.previewMessage))
+	}
+
+	if m.completion != nil {
+		sections = append(sections, m.renderCandidates())
+	}
+
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(strings.Join(sections, " "))
+}
+
+// renderCandidates renders the page of completion candidates containing the current one,
+// the current candidate is highlighted
+func (m *InputModel) renderCandidates() string {
+	candidates := m.completion.candidates
+	start := m.completion.index / completionPageSize * completionPageSize
+	end := min(start+completionPageSize, len(candidates))
+
+	var names []string
+	if start > 0 {
+		names = append(names, "…")
+	}
+
+	for i := start; i < end; i++ {
+		name := completionName(candidates[i])
+		if i == m.completion.index {
+			name = m.styles.candidateStyle.Render(name)
+		}
+
+		names = append(names, name)
+	}
+
+	if end < len(candidates) {
+		names = append(names, "…")
+	}
+
+	return strings.Join(names, " ")
 }
@@ -1,16 +1,35 @@
 package tui
 
 import (
+	"sort"
+	"strconv"
+
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/dinhhuy258/fm/pkg/config"
 )
 
+// KeyAction is an action resolved from key presses together with the key which triggered it
+type KeyAction struct {
+	Action *config.ActionConfig
+	Key    tea.KeyMsg
+}
+
 // KeyManager generates and manages keybindings from mode configurations
 type KeyManager struct {
 	modeManager *ModeManager
+
+	// pendingKeys contains the keys of an incomplete key sequence
+	pendingKeys []tea.KeyMsg
+	// sequenceID identifies the current pending sequence, it is used to ignore stale timeouts
+	sequenceID int
+	// count is the count prefix typed before a key binding, 0 means no count
+	count int
 }
 
+// maxCount limits the count prefix
+const maxCount = 9999
+
 // NewKeyManager creates a new key manager
 func NewKeyManager(modeManager *ModeManager) *KeyManager {
 	return &KeyManager{
@@ -18,27 +37,231 @@ func NewKeyManager(modeManager *ModeManager) *KeyManager {
 	}
 }
 
-// ResolveKeyAction resolves a tea.KeyMsg to its corresponding action configuration
-func (km *KeyManager) ResolveKeyAction(msg tea.KeyMsg) *config.ActionConfig {
-	keyStr := msg.String()
+// ResolveKeyAction resolves a tea.KeyMsg to the actions to execute.
+// When the key starts or continues a key sequence, no action is returned until the sequence is
+// complete. When a sequence does not match, the pending keys fall back to their own bindings or
+// the default action, and replay reports that the key must be resolved again once these actions
+// have run, since they may switch to another mode.
+func (km *KeyManager) ResolveKeyAction(msg tea.KeyMsg) (keyActions []KeyAction, replay bool) {
+	keyBindings := km.modeManager.GetKeyBindings(km.modeManager.GetCurrentMode())
+	if keyBindings == nil {
+		km.ResetPendingKeys()
+
+		return nil, false
+	}
+
+	keys := append(km.pendingKeys[:len(km.pendingKeys):len(km.pendingKeys)], msg)
+	exactAction, hasLonger := matchKeySequence(keyBindings, keyStrings(keys))
+
+	if hasLonger {
+		km.pendingKeys = keys
+		km.sequenceID++
+
+		return nil, false
+	}
+
+	if exactAction != nil {
+		km.ResetPendingKeys()
+
+		return []KeyAction{{Action: exactAction, Key: msg}}, false
+	}
+
+	if len(km.pendingKeys) == 0 {
+		action := resolveSingleKey(keyBindings, msg)
+		if action == nil {
+			return nil, false
+		}
+
+		return []KeyAction{{Action: action, Key: msg}}, false
+	}
+
+	// The sequence is broken, flush the pending keys, the new key is resolved on its own after
+	// them
+	keyActions = km.FlushPendingKeys()
+	if len(keyActions) == 0 {
+		return km.ResolveKeyAction(msg)
+	}
+
+	return keyActions, true
+}
+
+// FlushPendingKeys resolves the pending keys when a sequence is not completed, e.g. on timeout.
+// The binding of the whole pending sequence is used if it exists, otherwise each pending key
+// is resolved on its own.
+func (km *KeyManager) FlushPendingKeys() []KeyAction {
+	pendingKeys := km.pendingKeys
+	km.ResetPendingKeys()
+
+	if len(pendingKeys) == 0 {
+		return nil
+	}
+
+	keyBindings := km.modeManager.GetKeyBindings(km.modeManager.GetCurrentMode())
+	if keyBindings == nil {
+		return nil
+	}
+
+	lastKey := pendingKeys[len(pendingKeys)-1]
+
+	if action, _ := matchKeySequence(keyBindings, keyStrings(pendingKeys)); action != nil {
+		return []KeyAction{{Action: action, Key: lastKey}}
+	}
+
+	var keyActions []KeyAction
+
+	for _, key := range pendingKeys {
+		if action := resolveSingleKey(keyBindings, key); action != nil {
+			keyActions = append(keyActions, KeyAction{Action: action, Key: key})
+		}
+	}
+
+	return keyActions
+}
+
+// ResetPendingKeys discards the pending keys
+func (km *KeyManager) ResetPendingKeys() {
+	km.pendingKeys = nil
+	km.sequenceID++
+}
+
+// HasPendingKeys returns whether a key sequence is in progress
+func (km *KeyManager) HasPendingKeys() bool {
+	return len(km.pendingKeys) > 0
+}
+
+// GetPendingKeys returns the pending keys formatted for display
+func (km *KeyManager) GetPendingKeys() string {
+	return config.FormatKeySequence(keyStrings(km.pendingKeys))
+}
+
+// GetSequenceID returns the id of the current pending sequence
+func (km *KeyManager) GetSequenceID() int {
+	return km.sequenceID
+}
 
-	currentMode := km.modeManager.GetCurrentMode()
-	modeConfig := km.modeManager.GetModeConfig(currentMode)
-	if modeConfig == nil {
+// GetNextKeyMaps returns the bindings of the current mode which can follow the pending keys,
+// with the keys left to press, sorted by key
+func (km *KeyManager) GetNextKeyMaps() []keyMapEntry {
+	keyBindings := km.modeManager.GetKeyBindings(km.modeManager.GetCurrentMode())
+	if keyBindings == nil || len(km.pendingKeys) == 0 {
 		return nil
 	}
 
-	// Check on_keys first
-	if modeConfig.KeyBindings.OnKeys != nil {
-		if action, exists := modeConfig.KeyBindings.OnKeys[keyStr]; exists {
-			return action
+	keys := keyStrings(km.pendingKeys)
+
+	var keymaps []keyMapEntry
+
+	for binding, action := range keyBindings.OnKeys {
+		sequence := config.ParseKeySequence(binding)
+		if action == nil || len(sequence) <= len(keys) || !hasKeyPrefix(sequence, keys) {
+			continue
 		}
+
+		keymaps = append(keymaps, keyMapEntry{
+			key:         config.FormatKeySequence(sequence[len(keys):]),
+			description: describeAction(action),
+		})
+	}
+
+	sort.Slice(keymaps, func(i, j int) bool {
+		return keymaps[i].key < keymaps[j].key
+	})
+
+	return keymaps
+}
+
+// AppendCount appends the digit of the key to the count, it returns false for other keys
+func (km *KeyManager) AppendCount(key tea.KeyMsg) bool {
+	digit, ok := keyDigit(key)
+	if !ok {
+		return false
+	}
+
+	km.count = min(km.count*10+digit, maxCount)
+
+	return true
+}
+
+// GetCount returns the count prefix, 0 means no count
+func (km *KeyManager) GetCount() int {
+	return km.count
+}
+
+// ResetCount clears the count prefix
+func (km *KeyManager) ResetCount() {
+	km.count = 0
+}
+
+// resolveSingleKey resolves a key on its own: on_keys first, then on_number for digits,
+// then the default action
+func resolveSingleKey(keyBindings *config.KeyBindingsConfig, key tea.KeyMsg) *config.ActionConfig {
+	if action, _ := matchKeySequence(keyBindings, []string{key.String()}); action != nil {
+		return action
+	}
+
+	if _, isDigit := keyDigit(key); isDigit && keyBindings.OnNumber != nil {
+		return keyBindings.OnNumber
 	}
 
-	// Return default action if no specific key binding found
-	if modeConfig.KeyBindings.Default != nil {
-		return modeConfig.KeyBindings.Default
+	return keyBindings.Default
+}
+
+// keyDigit returns the digit of the key if the key is a digit
+func keyDigit(key tea.KeyMsg) (int, bool) {
+	if key.Type != tea.KeyRunes || len(key.Runes) != 1 || key.Alt {
+		return 0, false
+	}
+
+	digit, err := strconv.Atoi(string(key.Runes[0]))
+	if err != nil {
+		return 0, false
+	}
+
+	return digit, true
+}
+
+// matchKeySequence finds the binding of the given keys, it also reports whether a longer
+// sequence starts with these keys
+func matchKeySequence(
+	keyBindings *config.KeyBindingsConfig,
+	keys []string,
+) (*config.ActionConfig, bool) {
+	var exactAction *config.ActionConfig
+	hasLonger := false
+
+	for binding, action := range keyBindings.OnKeys {
+		sequence := config.ParseKeySequence(binding)
+		if len(sequence) < len(keys) || !hasKeyPrefix(sequence, keys) {
+			continue
+		}
+
+		if len(sequence) == len(keys) {
+			exactAction = action
+		} else {
+			hasLonger = true
+		}
+	}
+
+	return exactAction, hasLonger
+}
+
+// hasKeyPrefix checks if the sequence starts with the given keys
+func hasKeyPrefix(sequence []string, keys []string) bool {
+	for i, key := range keys {
+		if sequence[i] != key {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keyStrings converts the key messages to their string representation
+func keyStrings(keys []tea.KeyMsg) []string {
+	strs := make([]string, len(keys))
+	for i, key := range keys {
+		strs[i] = key.String()
 	}
 
-	return nil
+	return strs
 }
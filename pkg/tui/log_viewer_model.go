@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+)
+
+// LogViewerModel shows the history of the notifications, filtered by their minimum level
+type LogViewerModel struct {
+	width  int
+	height int
+
+	visible bool
+
+	viewport          viewport.Model
+	notificationModel *NotificationModel
+
+	// minLevel is the lowest level of the shown notifications
+	minLevel NotificationType
+	// newest is the newest notification of the history when the content was generated
+	newest *Notification
+
+	// Styles
+	titleStyle       lipgloss.Style
+	instructionStyle lipgloss.Style
+	borderStyle      lipgloss.Style
+	timeStyle        lipgloss.Style
+}
+
+// NewLogViewerModel creates a new log viewer model
+func NewLogViewerModel(notificationModel *NotificationModel) *LogViewerModel {
+	m := &LogViewerModel{
+		viewport:          viewport.New(0, 0),
+		notificationModel: notificationModel,
+		minLevel:          NotificationSuccess,
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Align(lipgloss.Center),
+	}
+	m.ReloadConfig()
+
+	return m
+}
+
+// ReloadConfig recreates the styles which depend on the config
+func (m *LogViewerModel) ReloadConfig() {
+	_, selFrameColor := getFrameColors()
+
+	m.instructionStyle = fromStyleConfig(config.AppConfig.General.FooterStyle).
+		Align(lipgloss.Center)
+	m.borderStyle = newBorderStyle(selFrameColor)
+	m.timeStyle = fromStyleConfig(config.AppConfig.General.InfoStyle)
+
+	if m.visible {
+		m.generateContent()
+	}
+}
+
+// SetSize updates the log viewer size
+func (m *LogViewerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	// Account for border, padding, title and instructions
+	m.viewport.Width = max(width-4, 0)
+	m.viewport.Height = max(height-6, 0)
+}
+
+// Show displays the log viewer scrolled to the newest notification and marks the errors read
+func (m *LogViewerModel) Show() {
+	m.visible = true
+	m.generateContent()
+	m.viewport.GotoBottom()
+	m.notificationModel.MarkAllRead()
+}
+
+// Hide closes the log viewer
+func (m *LogViewerModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the log viewer is visible
+func (m *LogViewerModel) IsVisible() bool {
+	return m.visible
+}
+
+// Refresh adds the notifications received while the log viewer is visible
+func (m *LogViewerModel) Refresh() {
+	if m.getNewest() == m.newest {
+		return
+	}
+
+	atBottom := m.viewport.AtBottom()
+	m.generateContent()
+	m.notificationModel.MarkAllRead()
+
+	// Follow the new notifications unless the history is being scrolled
+	if atBottom {
+		m.viewport.GotoBottom()
+	}
+}
+
+// setMinLevel shows only the notifications of the given level or higher
+func (m *LogViewerModel) setMinLevel(level NotificationType) {
+	m.minLevel = level
+	m.generateContent()
+	m.viewport.GotoBottom()
+}
+
+// generateContent renders the notifications of the history matching the level filter
+func (m *LogViewerModel) generateContent() {
+	history := m.notificationModel.GetHistory()
+	m.newest = m.getNewest()
+
+	lines := make([]string, 0, len(history))
+	for _, notification := range history {
+		if notification.Type < m.minLevel {
+			continue
+		}
+
+		style, prefix, suffix := m.notificationModel.GetStyle(notification.Type)
+		lines = append(lines, m.timeStyle.Render(notification.CreatedAt.Format(time.TimeOnly))+
+			" "+style.Render(prefix+notification.Message+suffix))
+	}
+
+	if len(lines) == 0 {
+		m.viewport.SetContent("No notifications")
+
+		return
+	}
+
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// getNewest returns the newest notification of the history, nil if the history is empty
+func (m *LogViewerModel) getNewest() *Notification {
+	history := m.notificationModel.GetHistory()
+	if len(history) == 0 {
+		return nil
+	}
+
+	return history[len(history)-1]
+}
+
+// getFilterName returns the description of the level filter
+func (m *LogViewerModel) getFilterName() string {
+	switch m.minLevel {
+	case NotificationWarning:
+		return "warnings and errors"
+	case NotificationError:
+		return "errors"
+	default:
+		return "all"
+	}
+}
+
+// Update handles the keys of the log viewer
+func (m *LogViewerModel) Update(msg tea.KeyMsg) {
+	if !m.visible {
+		return
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.Hide()
+	case "k", "up":
+		m.viewport.ScrollUp(1)
+	case "j", "down":
+		m.viewport.ScrollDown(1)
+	case "ctrl+u":
+		m.viewport.HalfPageUp()
+	case "ctrl+d":
+		m.viewport.HalfPageDown()
+	case "g", "home":
+		m.viewport.GotoTop()
+	case "G", "end":
+		m.viewport.GotoBottom()
+	case "a":
+		m.setMinLevel(NotificationSuccess)
+	case "w":
+		m.setMinLevel(NotificationWarning)
+	case "e":
+		m.setMinLevel(NotificationError)
+	}
+}
+
+// View renders the log viewer
+func (m *LogViewerModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	title := m.titleStyle.Render(fmt.Sprintf("Notifications (%s)", m.getFilterName()))
+
+	instructions := m.instructionStyle.Render(
+		"esc close • ↑↓ scroll • a all • w warnings • e errors",
+	)
+
+	rendered := m.borderStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		m.viewport.View(),
+		instructions,
+	))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		rendered,
+		lipgloss.WithWhitespaceChars(""),
+	)
+}
@@ -6,8 +6,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rivo/uniseg"
 
 	"github.com/dinhhuy258/fm/pkg/actions"
 	"github.com/dinhhuy258/fm/pkg/config"
@@ -25,6 +27,49 @@ type errorMessage struct {
 	Message string
 }
 
+// keySequenceTimeoutMessage is sent when the next key of a key sequence was not pressed in time
+type keySequenceTimeoutMessage struct {
+	sequenceID int
+}
+
+// whichKeyMessage is sent when the keys which can follow a pending key sequence should be shown
+type whichKeyMessage struct {
+	sequenceID int
+}
+
+// previewLoadedMessage is sent when a preview has been rendered in the background
+type previewLoadedMessage struct {
+	key    previewKey
+	result *previewResult
+}
+
+// directorySizeMessage is sent when the recursive size of a directory has been computed in the
+// background
+type directorySizeMessage struct {
+	generation int
+	path       string
+	size       int64
+	err        error
+}
+
+// directorySizeSortMessage is sent to sort the entries by size after some directory sizes have
+// been computed
+type directorySizeSortMessage struct {
+	generation int
+}
+
+// diskUsageScannedMessage is sent when a disk usage scan is done
+type diskUsageScannedMessage struct {
+	scanID int
+	root   *fs.DiskUsage
+	err    error
+}
+
+// diskUsageProgressMessage is sent periodically to refresh the progress of a disk usage scan
+type diskUsageProgressMessage struct {
+	scanID int
+}
+
 // directoryLoadedMessage indicates that a directory has been loaded
 type directoryLoadedMessage struct {
 	path    string
@@ -38,6 +83,8 @@ func (m Model) handleMessage(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleWindowSize(msg)
 	case tea.KeyMsg:
 		return m.handleKeyMsg(msg)
+	case tea.MouseMsg:
+		return m.handleMouseMsg(msg)
 	default:
 		return m.handleOtherMessage(msg)
 	}
@@ -45,22 +92,77 @@ func (m Model) handleMessage(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleWindowSize handles window resize events
 func (m Model) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
-	// Account for border: 4 characters width (2 border + 2 padding), 4 characters height
-	borderPadding := 4
-	availableWidth := max(msg.Width-borderPadding, 1)
-	availableHeight := max(msg.Height-borderPadding, 1)
+	m.width = msg.Width
+	m.height = msg.Height
+	m.updateLayout()
+
+	return m, nil
+}
+
+// updateLayout applies the layout rules for the window size and resizes the components
+func (m *Model) updateLayout() {
+	layoutConfig := config.AppConfig.General.Layout
+
+	m.showBorder = true
+	m.compactHeader = false
 
-	headerHeight := 3
-	footerHeight := 1
-	interactiveHeight := 1
-	availableExplorerHeight := availableHeight - headerHeight - footerHeight - interactiveHeight
+	if layoutConfig != nil {
+		m.showBorder = layoutConfig.Border &&
+			m.width >= layoutConfig.BorderMinWidth && m.height >= layoutConfig.BorderMinHeight
+		m.compactHeader = m.height < layoutConfig.CompactHeaderHeight
+	}
+
+	availableWidth, availableHeight := m.width, m.height
+	if m.showBorder {
+		availableWidth -= borderWidth
+		availableHeight -= borderHeight
+	}
 
-	m.helpModel.SetSize(msg.Width, msg.Height)
+	availableWidth = max(availableWidth, 1)
+	availableExplorerHeight := max(
+		availableHeight-m.getHeaderHeight()-footerHeight-interactiveHeight, 1)
+
+	m.helpModel.SetSize(m.width, m.height)
+	m.selectionPanel.SetSize(m.width, m.height)
+	m.logViewer.SetSize(m.width, m.height)
+	m.entryInfo.SetSize(m.width, m.height)
 	m.inputModel.SetSize(availableWidth, 1)
 	m.notificationModel.SetSize(availableWidth, 1)
-	m.explorerModel.SetSize(availableWidth, availableExplorerHeight)
+	m.diskUsage.SetSize(availableWidth)
+
+	// The preview takes its share of the width if both the preview and the explorer fit
+	explorerWidth := availableWidth
+	m.showPreview = false
+
+	if previewConfig := config.AppConfig.General.Preview; previewConfig != nil &&
+		m.previewModel.IsVisible() {
+		previewWidth := availableWidth * previewConfig.WidthPercentage / 100
+		if previewWidth >= minPreviewWidth && availableWidth-previewWidth >= minPreviewWidth {
+			explorerWidth -= previewWidth
+			m.showPreview = true
+			m.previewModel.SetSize(previewWidth, availableExplorerHeight)
+		}
+	}
 
-	return m, nil
+	m.explorerModel.SetSize(explorerWidth, availableExplorerHeight)
+}
+
+// getHeaderHeight returns the height of the header for the current layout
+func (m Model) getHeaderHeight() int {
+	if m.compactHeader {
+		return compactHeaderHeight
+	}
+
+	return headerHeight
+}
+
+// getContentOffset returns the position of the content in the window
+func (m Model) getContentOffset() (int, int) {
+	if m.showBorder {
+		return borderOffsetX, borderOffsetY
+	}
+
+	return 0, 0
 }
 
 // handleKeyMsg handles keyboard input events
@@ -71,7 +173,23 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if msg.String() == HelpToggleKey {
+	if m.selectionPanel.IsVisible() {
+		return m, m.selectionPanel.Update(msg)
+	}
+
+	if m.logViewer.IsVisible() {
+		m.logViewer.Update(msg)
+
+		return m, nil
+	}
+
+	if m.entryInfo.IsVisible() {
+		m.entryInfo.Update(msg)
+
+		return m, nil
+	}
+
+	if msg.String() == HelpToggleKey && !m.keyManager.HasPendingKeys() {
 		m.helpModel.Show()
 
 		return m, nil
@@ -90,17 +208,64 @@ func (m Model) handleOtherMessage(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case directoryLoadedMessage:
 		m.currentPath = msg.path
 		m.explorerModel.SetEntries(msg.entries)
+		clear(m.directoryInfo)
 
 		return m, nil
 	case PipeMessage:
 		return m.handlePipeMessage(msg.Command)
+	case actions.QuitMessage:
+		return m.handleQuitMessage()
+	case actions.ReloadConfigMessage:
+		return m.handleReloadConfigMessage()
+	case actions.SetThemeMessage:
+		return m.handleSetThemeMessage(msg)
+	case actions.ExportLogMessage:
+		return m.handleExportLogMessage(msg)
+	case configWatchMessage:
+		return m.handleConfigWatchMessage()
+	case keySequenceTimeoutMessage:
+		return m.handleKeySequenceTimeoutMessage(msg)
+	case previewLoadedMessage:
+		m.previewModel.SetResult(msg.key, msg.result)
+
+		return m, nil
+	case directorySizeMessage:
+		return m.handleDirectorySizeMessage(msg)
+	case directorySizeSortMessage:
+		if m.directorySizes.EndSort(msg.generation) && m.sortType == types.SortTypeSize {
+			m.explorerModel.SortEntries(m.sortType, m.reverse)
+		}
+
+		return m, nil
+	case diskUsageScannedMessage:
+		return m.handleDiskUsageScannedMessage(msg)
+	case diskUsageProgressMessage:
+		return m, m.diskUsage.Tick(msg.scanID)
+	case whichKeyMessage:
+		m.whichKeySequenceID = msg.sequenceID
+
+		return m, nil
 	case actions.ModeChangedMessage:
-		m.modeManager.SwitchToMode(msg.Mode)
+		// The disk usage mode replaces the default mode while the entries are listed by disk
+		// usage, e.g. when a mode entered from it switches back to the default mode
+		mode := msg.Mode
+		if mode == defaultMode && m.diskUsage.IsActive() {
+			mode = diskUsageMode
+		}
+
+		// A range selection left when the mode changes is kept as selection
+		if mode != m.modeManager.GetCurrentMode() {
+			m.explorerModel.CommitRange()
+		}
+
+		m.modeManager.SwitchToMode(mode)
+		m.keyManager.ResetPendingKeys()
+		m.keyManager.ResetCount()
 		// Notification is always shown by default
 		m.notificationModel.Show()
 		m.inputModel.Hide()
 
-		return m, nil
+		return m, m.configureInput()
 	case AutoClearMessage:
 		m.notificationModel.ClearNotification()
 
@@ -118,11 +283,19 @@ func (m Model) handleOtherMessage(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case actions.SetInputBufferMessage:
 		m.notificationModel.Hide()
-		m.inputModel.Show(msg.Value)
+		m.inputModel.Show(msg.Value, m.currentPath, m.getInputHistory())
 
 		return m, nil
 	case actions.UpdateInputBufferFromKeyMessage:
 		return m, m.inputModel.Update(msg.Key)
+	case actions.UpdateCountFromKeyMessage:
+		m.keyManager.AppendCount(msg.Key)
+
+		return m, nil
+	case actions.ClearCountMessage:
+		m.keyManager.ResetCount()
+
+		return m, nil
 	case actions.FocusPathMessage:
 		dir := filepath.Dir(msg.Path)
 		if dir == m.currentPath {
@@ -147,6 +320,8 @@ func (m Model) handleOtherMessage(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleFocusByIndexMessage(msg)
 	case actions.SelectionMessage:
 		return m.handleSelectionMessage(msg)
+	case actions.MouseMessage:
+		return m.handleMouseMessage(msg)
 	case actions.ToggleSelectionByPathMessage:
 		return m.handleToggleSelectionByPathMessage(msg)
 	case actions.UIMessage:
@@ -166,14 +341,317 @@ func (m Model) handleOtherMessage(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyMap handles key presses and resolves them to actions
 func (m Model) handleKeyMap(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	action := m.keyManager.ResolveKeyAction(msg)
-	if action != nil {
-		return m, m.actionHandler.ExecuteMessages(action.Messages, msg)
+	keyActions, replay := m.keyManager.ResolveKeyAction(msg)
+	if replay {
+		// The key is handled again once the actions of the broken sequence have run, in the
+		// mode they may have switched to
+		cmds := m.keyActionCmds(keyActions)
+		cmds = append(cmds, func() tea.Msg {
+			return msg
+		})
+
+		return m, tea.Sequence(cmds...)
 	}
 
-	return m, m.notificationModel.ShowNotification(NotificationWarning,
-		fmt.Sprintf("No action found for key: %s", msg.String()),
-	)
+	var cmds []tea.Cmd
+	if msg.Type == tea.KeyEnter {
+		cmds = append(cmds, m.recordInputHistory())
+	}
+
+	if m.keyManager.HasPendingKeys() {
+		cmds = append(cmds, m.waitForNextKey(), m.showWhichKey())
+	} else if len(keyActions) == 0 {
+		return m, m.notificationModel.ShowNotification(NotificationWarning,
+			fmt.Sprintf("No action found for key: %s", msg.String()),
+		)
+	}
+
+	cmds = append(cmds, m.executeKeyActions(keyActions))
+
+	return m, tea.Batch(cmds...)
+}
+
+// handleMouseMsg resolves a mouse event to the action bound in on_mouse
+func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.isOverlayVisible() {
+		return m, nil
+	}
+
+	event, target, ok := m.hitTestMouse(msg)
+	if !ok {
+		return m, nil
+	}
+
+	keyBindings := m.modeManager.GetKeyBindings(m.modeManager.GetCurrentMode())
+	if keyBindings == nil || keyBindings.OnMouse[event] == nil {
+		return m, nil
+	}
+
+	m.mouseManager.SetTarget(target)
+
+	return m, m.executeKeyActions([]KeyAction{{Action: keyBindings.OnMouse[event]}})
+}
+
+// hitTestMouse finds the mouse event and its target from the position of the mouse
+func (m Model) hitTestMouse(msg tea.MouseMsg) (string, MouseTarget, bool) {
+	target := MouseTarget{Index: -1}
+
+	if msg.Action != tea.MouseActionPress {
+		return "", target, false
+	}
+
+	offsetX, offsetY := m.getContentOffset()
+	x := msg.X - offsetX
+	y := msg.Y - offsetY
+	tableRow := m.getHeaderHeight()
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return config.MouseEventWheelUp, target, true
+	case tea.MouseButtonWheelDown:
+		return config.MouseEventWheelDown, target, true
+	case tea.MouseButtonLeft:
+	default:
+		return "", target, false
+	}
+
+	switch {
+	case y == titleRow:
+		path, ok := m.getPathAt(x)
+		target.Path = path
+
+		return config.MouseEventPathClick, target, ok
+	case y == tableRow:
+		target.Column = m.explorerModel.GetColumnAt(x)
+
+		return config.MouseEventHeaderClick, target, target.Column != ""
+	case y > tableRow:
+		if explorerWidth, _ := m.explorerModel.GetSize(); x >= explorerWidth {
+			return "", target, false
+		}
+
+		index, ok := m.explorerModel.GetEntryIndexAtRow(y - tableRow)
+		if !ok {
+			return "", target, false
+		}
+
+		target.Index = index
+
+		return m.mouseManager.Click(index, time.Now()), target, true
+	}
+
+	return "", target, false
+}
+
+// getPathAt returns the ancestor of the current path whose last component is at the given x
+// position of the title
+func (m Model) getPathAt(x int) (string, bool) {
+	offset := x - uniseg.StringWidth(ExplorerTitle+": ")
+	if offset < 0 {
+		return "", false
+	}
+
+	// Find the byte index of the character at the offset
+	index := -1
+	width := 0
+
+	for i, char := range m.currentPath {
+		width += uniseg.StringWidth(string(char))
+		if width > offset {
+			index = i
+
+			break
+		}
+	}
+
+	if index < 0 {
+		return "", false
+	}
+
+	end := strings.Index(m.currentPath[index:], "/")
+	if end < 0 {
+		return m.currentPath, true
+	}
+
+	if index+end == 0 {
+		return "/", true
+	}
+
+	return m.currentPath[:index+end], true
+}
+
+// handleMouseMessage processes actions on the target of the last mouse event
+func (m Model) handleMouseMessage(msg actions.MouseMessage) (tea.Model, tea.Cmd) {
+	target := m.mouseManager.GetTarget()
+
+	switch msg.Action {
+	case actions.MouseActionFocusEntry:
+		if target.Index >= 0 {
+			m.explorerModel.SetFocusByIndex(target.Index)
+		}
+	case actions.MouseActionSortByColumn:
+		// The index column and the column the entries are already sorted by reverse the order
+		sortType := actions.SortTypeReverse
+
+		columnSortTypes := map[string]types.SortType{
+			columnName: types.SortTypeName,
+			columnSize: types.SortTypeSize,
+			columnDate: types.SortTypeDate,
+		}
+		if columnSortType, exists := columnSortTypes[target.Column]; exists &&
+			m.sortType != columnSortType {
+			sortType = string(columnSortType)
+		}
+
+		if target.Column != "" {
+			return m.handleSortingMessage(actions.SortingMessage{SortType: sortType})
+		}
+	case actions.MouseActionChangeDirectory:
+		if target.Path != "" {
+			return m.handleChangeDirectoryMessage(actions.ChangeDirectoryMessage{Path: target.Path})
+		}
+	}
+
+	return m, nil
+}
+
+// handleKeySequenceTimeoutMessage flushes the pending keys of an incomplete key sequence
+func (m Model) handleKeySequenceTimeoutMessage(msg keySequenceTimeoutMessage) (tea.Model, tea.Cmd) {
+	if msg.sequenceID != m.keyManager.GetSequenceID() {
+		return m, nil
+	}
+
+	return m, m.executeKeyActions(m.keyManager.FlushPendingKeys())
+}
+
+// waitForNextKey starts the timeout of the pending key sequence
+func (m Model) waitForNextKey() tea.Cmd {
+	sequenceID := m.keyManager.GetSequenceID()
+	timeout := time.Duration(config.AppConfig.General.KeySequenceTimeout) * time.Millisecond
+
+	return tea.Tick(timeout, func(time.Time) tea.Msg {
+		return keySequenceTimeoutMessage{sequenceID: sequenceID}
+	})
+}
+
+// executeKeyActions executes the messages of the resolved key actions in order
+func (m Model) executeKeyActions(keyActions []KeyAction) tea.Cmd {
+	cmds := m.keyActionCmds(keyActions)
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	return tea.Sequence(cmds...)
+}
+
+// keyActionCmds returns the commands of the messages of the resolved key actions in order.
+// An action made only of repeatable messages is repeated count times, the count is consumed
+// unless all messages keep it (e.g. while the count is being typed).
+func (m Model) keyActionCmds(keyActions []KeyAction) []tea.Cmd {
+	var cmds []tea.Cmd
+
+	for _, keyAction := range keyActions {
+		// The digit is added to the count at once, a message would let the next key run before it
+		// is counted
+		if isCountAction(keyAction.Action) {
+			m.keyManager.AppendCount(keyAction.Key)
+
+			continue
+		}
+
+		repeatable, keepsCount := messageTraits(keyAction.Action.Messages)
+
+		times := 1
+		if count := m.keyManager.GetCount(); repeatable && count > 0 {
+			times = count
+		}
+
+		if !keepsCount {
+			m.keyManager.ResetCount()
+		}
+
+		for range times {
+			for _, message := range keyAction.Action.Messages {
+				if cmd := m.actionHandler.ExecuteMessage(message, keyAction.Key); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			}
+		}
+	}
+
+	return cmds
+}
+
+// getInputHistory returns the input history of the current mode, nil if the history is disabled
+func (m Model) getInputHistory() []string {
+	if !m.inputModel.IsHistoryEnabled() {
+		return nil
+	}
+
+	return m.history.GetEntries(m.modeManager.GetCurrentMode())
+}
+
+// recordInputHistory adds the submitted input value to the history of the current mode, it
+// returns the command saving the history in the background
+func (m Model) recordInputHistory() tea.Cmd {
+	value := m.inputModel.GetValue()
+	if !m.inputModel.IsVisible() || !m.inputModel.IsHistoryEnabled() || value == "" {
+		return nil
+	}
+
+	m.history.Add(m.modeManager.GetCurrentMode(), value)
+	inputHistory := m.history
+
+	return func() tea.Msg {
+		if err := inputHistory.Save(); err != nil {
+			return actions.LogMessage{
+				Level:   actions.LogLevelError,
+				Message: fmt.Sprintf("Failed to save history: %v", err),
+			}
+		}
+
+		return nil
+	}
+}
+
+// configureInput applies the input config of the current mode to the input
+func (m Model) configureInput() tea.Cmd {
+	inputConfig := m.modeManager.GetInput(m.modeManager.GetCurrentMode())
+
+	var previewer InputPreviewer
+	if inputConfig != nil && inputConfig.Preview == config.PreviewMatchCount {
+		previewer = m.previewMatchCount
+	}
+
+	validator, err := config.NewInputValidator(inputConfig, m.luaEngine)
+	m.inputModel.Configure(inputConfig, validator, previewer)
+
+	if err != nil {
+		return m.notificationModel.ShowNotification(NotificationError,
+			fmt.Sprintf("Invalid input config: %v", err),
+		)
+	}
+
+	return nil
+}
+
+// previewMatchCount describes the number of entries whose name matches the pattern
+func (m Model) previewMatchCount(pattern string) string {
+	if pattern == "" {
+		return ""
+	}
+
+	matcher, err := fs.NewPatternMatcher(pattern)
+	if err != nil {
+		return "invalid pattern"
+	}
+
+	count := m.explorerModel.CountMatching(matcher)
+	if count == 1 {
+		return "1 match"
+	}
+
+	return fmt.Sprintf("%d matches", count)
 }
 
 // handlePipeMessage processes messages received from the pipe
@@ -196,6 +674,29 @@ func (m Model) handlePipeMessage(command string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleQuitMessage fires the quit event then quits the application.
+// Messages returned from the quit hooks are executed before quitting, a quit sent by a hook quits
+// right away. The error of the hooks is kept to be reported once fm exited.
+func (m Model) handleQuitMessage() (tea.Model, tea.Cmd) {
+	if m.quitting {
+		return m, tea.Quit
+	}
+
+	m.quitting = true
+
+	messages, err := config.RunHooks(m.luaEngine, config.EventQuit, map[string]string{
+		"path":       m.currentPath,
+		"focus_path": m.getFocusPath(),
+	})
+	m.quitError = err
+
+	if len(messages) == 0 {
+		return m, tea.Quit
+	}
+
+	return m, tea.Sequence(m.actionHandler.ExecuteMessages(messages, tea.KeyMsg{}), tea.Quit)
+}
+
 // handleNavigationMessage processes navigation actions
 func (m Model) handleNavigationMessage(msg actions.NavigationMessage) (tea.Model, tea.Cmd) {
 	switch msg.Action {
@@ -261,6 +762,36 @@ func (m Model) handleSelectionMessage(msg actions.SelectionMessage) (tea.Model,
 		m.explorerModel.ClearSelections()
 	case actions.SelectionActionAll:
 		m.explorerModel.SelectAll()
+	case actions.SelectionActionInvert:
+		m.explorerModel.InvertSelection()
+	case actions.SelectionActionByPattern, actions.SelectionActionDeselectByPattern:
+		matcher, err := fs.NewPatternMatcher(msg.Pattern)
+		if err != nil {
+			return m, m.notificationModel.ShowNotification(NotificationError, err.Error())
+		}
+
+		if msg.Action == actions.SelectionActionByPattern {
+			m.explorerModel.SelectMatching(matcher)
+		} else {
+			m.explorerModel.DeselectMatching(matcher)
+		}
+	case actions.SelectionActionSameExtension:
+		if entry := m.explorerModel.GetFocusedEntry(); entry != nil {
+			m.explorerModel.SelectMatching(fs.NewExtensionMatcher(entry))
+		}
+	case actions.SelectionActionByType:
+		matcher, err := fs.NewTypeMatcher(msg.EntryType)
+		if err != nil {
+			return m, m.notificationModel.ShowNotification(NotificationError, err.Error())
+		}
+
+		m.explorerModel.SelectMatching(matcher)
+	case actions.SelectionActionRangeStart:
+		m.explorerModel.StartRange()
+	case actions.SelectionActionRangeCommit:
+		m.explorerModel.CommitRange()
+	case actions.SelectionActionRangeCancel:
+		m.explorerModel.CancelRange()
 	}
 
 	return m, nil
@@ -279,8 +810,74 @@ func (m Model) handleUIMessage(msg actions.UIMessage) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		return m, nil
+	case actions.UIActionToggleSelectionPanel:
+		if m.selectionPanel.IsVisible() {
+			m.selectionPanel.Hide()
+		} else {
+			m.selectionPanel.Show()
+		}
+
+		return m, nil
+	case actions.UIActionToggleLogViewer:
+		if m.logViewer.IsVisible() {
+			m.logViewer.Hide()
+		} else {
+			m.logViewer.Show()
+		}
+
+		return m, nil
+	case actions.UIActionTogglePreview:
+		if m.previewModel.IsVisible() {
+			m.previewModel.Hide()
+		} else {
+			m.previewModel.Show()
+		}
+
+		m.updateLayout()
+
+		return m, nil
+	case actions.UIActionToggleDirectorySizes:
+		m.directorySizes.Toggle()
+
+		// Reload the directory to compute the sizes or to drop them
+		if err := m.loadDirectory(m.currentPath); err != nil {
+			return m, func() tea.Msg {
+				return errorMessage{
+					Message: err.Error(),
+				}
+			}
+		}
+
+		return m, nil
+	case actions.UIActionDiskUsage:
+		return m, m.diskUsage.Scan(m.currentPath)
+	case actions.UIActionExitDiskUsage:
+		isActive := m.diskUsage.IsActive()
+		m.diskUsage.Exit()
+		m.syncDiskUsageMode()
+
+		// List the entries of the directory as usual
+		if isActive {
+			if err := m.loadDirectory(m.currentPath); err != nil {
+				return m, func() tea.Msg {
+					return errorMessage{
+						Message: err.Error(),
+					}
+				}
+			}
+		}
+
+		return m, nil
+	case actions.UIActionShowEntryInfo:
+		if entry := m.explorerModel.GetFocusedEntry(); entry != nil {
+			m.entryInfo.Show(entry)
+		}
+
 		return m, nil
 	case actions.UIActionRefresh:
+		m.directorySizes.ClearCache()
+
 		if err := m.loadDirectory(m.currentPath); err != nil {
 			return m, func() tea.Msg {
 				return errorMessage{
@@ -295,8 +892,63 @@ func (m Model) handleUIMessage(msg actions.UIMessage) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleDirectorySizeMessage shows the recursive size of a directory of the current listing and
+// schedules a sort of the entries when they are sorted by size
+func (m Model) handleDirectorySizeMessage(msg directorySizeMessage) (tea.Model, tea.Cmd) {
+	if !m.directorySizes.IsCurrent(msg.generation) {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		return m, func() tea.Msg {
+			return actions.LogMessage{
+				Level:   actions.LogLevelWarning,
+				Message: fmt.Sprintf("Failed to compute the size of %s: %v", msg.path, msg.err),
+			}
+		}
+	}
+
+	m.explorerModel.SetDirectorySize(msg.path, msg.size)
+
+	if m.sortType == types.SortTypeSize {
+		return m, m.directorySizes.ScheduleSort()
+	}
+
+	return m, nil
+}
+
+// handleDiskUsageScannedMessage lists the entries of the scanned directory by disk usage
+func (m Model) handleDiskUsageScannedMessage(msg diskUsageScannedMessage) (tea.Model, tea.Cmd) {
+	if !m.diskUsage.SetResult(msg) {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		m.syncDiskUsageMode()
+
+		return m, m.notificationModel.ShowNotification(NotificationError,
+			fmt.Sprintf("Failed to scan the disk usage: %v", msg.err))
+	}
+
+	if err := m.loadDirectory(msg.root.Path); err != nil {
+		return m, func() tea.Msg {
+			return errorMessage{
+				Message: err.Error(),
+			}
+		}
+	}
+
+	return m, nil
+}
+
 // handleSortingMessage processes sorting actions
 func (m Model) handleSortingMessage(msg actions.SortingMessage) (tea.Model, tea.Cmd) {
+	// The entries listed by disk usage are always sorted by usage
+	if m.diskUsage.IsActive() {
+		return m, m.notificationModel.ShowNotification(NotificationWarning,
+			"Sorting is ignored while the entries are listed by disk usage")
+	}
+
 	switch msg.SortType {
 	case actions.SortTypeReverse:
 		m.reverse = !m.reverse
@@ -359,6 +1011,26 @@ func (m Model) handleChangeDirectoryMessage(
 	return m, nil
 }
 
+// handleExportLogMessage writes the notification history to a file, a relative path is
+// resolved from the current directory
+func (m Model) handleExportLogMessage(msg actions.ExportLogMessage) (tea.Model, tea.Cmd) {
+	path := msg.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.currentPath, path)
+	}
+
+	if err := m.notificationModel.ExportHistory(path); err != nil {
+		return m, m.notificationModel.ShowNotification(NotificationError,
+			fmt.Sprintf("Failed to export log: %v", err),
+		)
+	}
+
+	return m, m.notificationModel.ShowNotification(NotificationSuccess,
+		fmt.Sprintf("Exported %d notification(s) to %s",
+			len(m.notificationModel.GetHistory()), path),
+	)
+}
+
 // handleBashExecution processes bash execution with environment setup
 func (m Model) handleBashExecution(script string, silent bool) (tea.Model, tea.Cmd) {
 	selections := m.explorerModel.GetSelectedPaths()
@@ -422,6 +1094,39 @@ func (m Model) handleBashExecution(script string, silent bool) (tea.Model, tea.C
 	})
 }
 
+// messageTraits reports whether all messages are repeatable and whether they all keep the count
+func messageTraits(messages []*config.MessageConfig) (bool, bool) {
+	repeatable := len(messages) > 0
+	keepsCount := len(messages) > 0
+
+	for _, message := range messages {
+		spec, exists := actions.LookupMessage(message.Name)
+		if !exists {
+			return false, false
+		}
+
+		repeatable = repeatable && spec.Repeatable
+		keepsCount = keepsCount && spec.KeepsCount
+	}
+
+	return repeatable, keepsCount
+}
+
+// isCountAction returns whether the action only adds the digit of its key to the count
+func isCountAction(action *config.ActionConfig) bool {
+	if len(action.Messages) == 0 {
+		return false
+	}
+
+	for _, message := range action.Messages {
+		if message.Name != updateCountFromKeyMessage {
+			return false
+		}
+	}
+
+	return true
+}
+
 // writeSelectionsToFile writes selected file paths to the selection pipe file
 func writeSelectionsToFile(path string, selections []string) error {
 	const perm = 0600
@@ -443,12 +1148,62 @@ func (m *Model) loadDirectory(path string) error {
 		return fmt.Errorf("failed to load directory %s: %w", path, err)
 	}
 
+	// The entries of the scanned directories are listed by disk usage, the disk usage of the
+	// directory is updated in place, e.g. after deleting entries
+	usage := m.diskUsage.Find(path)
+	if usage == nil {
+		m.diskUsage.Hide()
+	} else if err := usage.Refresh(); err != nil {
+		return fmt.Errorf("failed to update the disk usage of %s: %w", path, err)
+	} else {
+		sortByDiskUsage(entries, usage)
+	}
+
 	m.currentPath = path
 	m.explorerModel.SetEntries(entries)
+	m.explorerModel.SetDiskUsage(usage)
+	m.explorerModel.SetShowDirectorySizes(m.directorySizes.IsEnabled())
+	m.entryErrorSummary = summarizeEntryErrors(entries)
+	m.entriesLoaded = true
+	clear(m.directoryInfo)
+	m.syncDiskUsageMode()
 
 	return nil
 }
 
+// syncDiskUsageMode switches between the default mode and the disk usage mode when the disk usage
+// listing starts or ends, e.g. when the scan is done or when leaving the scanned directory. The
+// other modes are kept.
+func (m *Model) syncDiskUsageMode() {
+	switch mode := m.modeManager.GetCurrentMode(); {
+	case mode == defaultMode && m.diskUsage.IsActive():
+		m.modeManager.SwitchToMode(diskUsageMode)
+	case mode == diskUsageMode && !m.diskUsage.IsActive() && !m.diskUsage.IsScanning():
+		m.modeManager.SwitchToMode(defaultMode)
+	}
+}
+
+// summarizeEntryErrors describes the entries which can't be read, empty if there is none
+func summarizeEntryErrors(entries []fs.IEntry) string {
+	var failed []fs.IEntry
+
+	for _, entry := range entries {
+		if entry.GetError() != nil {
+			failed = append(failed, entry)
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("Failed to read %s: %v", failed[0].GetName(), failed[0].GetError())
+	default:
+		return fmt.Sprintf("Failed to read %d entries, e.g. %s: %v",
+			len(failed), failed[0].GetName(), failed[0].GetError())
+	}
+}
+
 // parseCommand parses a shell command line, properly handling:
 // - Single quotes: preserve all characters literally (no variable expansion)
 // - Double quotes: preserve spaces but allow variable expansion
@@ -9,6 +9,11 @@ type ModeManager struct {
 	currentMode  string
 	customModes  map[string]*config.ModeConfig
 	builtinModes map[string]*config.ModeConfig
+
+	// keyBindings contains the key bindings of each mode with inherited and global bindings resolved
+	keyBindings map[string]*config.KeyBindingsConfig
+	// inputs contains the input of each mode with the inherited input resolved
+	inputs map[string]*config.InputConfig
 }
 
 // NewModeManager creates a new mode manager from the config
@@ -18,11 +23,27 @@ func NewModeManager() *ModeManager {
 		currentMode:  "default",
 		customModes:  cfg.Modes.Customs,
 		builtinModes: cfg.Modes.Builtins,
+		keyBindings:  resolveKeyBindings(cfg.Modes),
+		inputs:       resolveInputs(cfg.Modes),
 	}
 
 	return mm
 }
 
+// ReloadConfig reloads the mode definitions from the current config.
+// The current mode is kept if it still exists, otherwise fm falls back to the default mode.
+func (mm *ModeManager) ReloadConfig() {
+	cfg := config.AppConfig
+	mm.customModes = cfg.Modes.Customs
+	mm.builtinModes = cfg.Modes.Builtins
+	mm.keyBindings = resolveKeyBindings(cfg.Modes)
+	mm.inputs = resolveInputs(cfg.Modes)
+
+	if !mm.modeExists(mm.currentMode) {
+		mm.currentMode = "default"
+	}
+}
+
 // GetCurrentMode returns the name of the current mode
 func (mm *ModeManager) GetCurrentMode() string {
 	return mm.currentMode
@@ -52,6 +73,50 @@ func (mm *ModeManager) GetModeConfig(modeName string) *config.ModeConfig {
 	return nil
 }
 
+// GetKeyBindings returns the key bindings of the specified mode, including the bindings inherited
+// from the modes it extends and the global bindings
+func (mm *ModeManager) GetKeyBindings(modeName string) *config.KeyBindingsConfig {
+	return mm.keyBindings[modeName]
+}
+
+// GetInput returns the input config of the specified mode, including the input inherited from the
+// modes it extends, nil if the mode has no input
+func (mm *ModeManager) GetInput(modeName string) *config.InputConfig {
+	return mm.inputs[modeName]
+}
+
+// resolveKeyBindings resolves the key bindings of all modes.
+// Broken inheritance chains are reported by the config validation, the bindings resolved so far
+// are used.
+func resolveKeyBindings(modesConfig *config.ModesConfig) map[string]*config.KeyBindingsConfig {
+	keyBindings := make(map[string]*config.KeyBindingsConfig)
+
+	for name := range modesConfig.Builtins {
+		keyBindings[name], _ = modesConfig.ResolveKeyBindings(name)
+	}
+
+	for name := range modesConfig.Customs {
+		keyBindings[name], _ = modesConfig.ResolveKeyBindings(name)
+	}
+
+	return keyBindings
+}
+
+// resolveInputs resolves the input configs of all modes, the modes without input are left out
+func resolveInputs(modesConfig *config.ModesConfig) map[string]*config.InputConfig {
+	inputs := make(map[string]*config.InputConfig)
+
+	for _, modes := range []map[string]*config.ModeConfig{modesConfig.Builtins, modesConfig.Customs} {
+		for name := range modes {
+			if input := modesConfig.ResolveInput(name); input != nil {
+				inputs[name] = input
+			}
+		}
+	}
+
+	return inputs
+}
+
 // modeExists checks if a mode exists in custom or builtin modes
 func (mm *ModeManager) modeExists(modeName string) bool {
 	if _, exists := mm.customModes[modeName]; exists {
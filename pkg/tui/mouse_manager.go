@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+)
+
+// doubleClickInterval is the maximum delay between the two clicks of a double click
+const doubleClickInterval = 500 * time.Millisecond
+
+// MouseTarget is what is under the mouse: an entry, a column header or an ancestor of the
+// current path
+type MouseTarget struct {
+	// Index is the index of the entry, -1 if the mouse is not over an entry
+	Index  int
+	Column string
+	Path   string
+}
+
+// MouseManager keeps the target of the last mouse event and detects double clicks
+type MouseManager struct {
+	target MouseTarget
+
+	lastClickTime  time.Time
+	lastClickIndex int
+}
+
+// NewMouseManager creates a new mouse manager
+func NewMouseManager() *MouseManager {
+	return &MouseManager{
+		target:         MouseTarget{Index: -1},
+		lastClickIndex: -1,
+	}
+}
+
+// SetTarget sets the target of the last mouse event
+func (mm *MouseManager) SetTarget(target MouseTarget) {
+	mm.target = target
+}
+
+// GetTarget returns the target of the last mouse event
+func (mm *MouseManager) GetTarget() MouseTarget {
+	return mm.target
+}
+
+// Click registers a click on the entry at the given index and returns the mouse event:
+// a double click if the same entry was clicked within doubleClickInterval, a click otherwise
+func (mm *MouseManager) Click(index int, now time.Time) string {
+	if index == mm.lastClickIndex && now.Sub(mm.lastClickTime) <= doubleClickInterval {
+		// A third click starts a new double click
+		mm.lastClickIndex = -1
+
+		return config.MouseEventDoubleClick
+	}
+
+	mm.lastClickIndex = index
+	mm.lastClickTime = now
+
+	return config.MouseEventClick
+}
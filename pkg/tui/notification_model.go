@@ -1,6 +1,9 @@
 package tui
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,6 +14,9 @@ import (
 
 const autoClearNotificationDuration = 5 * time.Second
 
+// maxNotificationHistory is the number of notifications kept in the history
+const maxNotificationHistory = 1000
+
 // NotificationType represents different types of status notifications
 type NotificationType int8
 
@@ -21,6 +27,20 @@ const (
 	NotificationError
 )
 
+// String returns the name of the notification type
+func (t NotificationType) String() string {
+	switch t {
+	case NotificationSuccess:
+		return "success"
+	case NotificationWarning:
+		return "warning"
+	case NotificationError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
 // Notification represents a single status notification with timestamp
 type Notification struct {
 	Type      NotificationType
@@ -44,22 +64,35 @@ type NotificationModel struct {
 	activeNotification *Notification
 	styles             *NotificationStyles
 	isVisible          bool
+
+	// history contains the notifications from the oldest to the newest
+	history []*Notification
+	// unreadErrors is the number of errors added to the history since it was last read
+	unreadErrors int
 }
 
 // NewNotificationModel creates a new notification model
 func NewNotificationModel() *NotificationModel {
-	notificationStyle := &NotificationStyles{
+	return &NotificationModel{
+		activeNotification: nil,
+		isVisible:          true, // Default to visible
+		styles:             newNotificationStyles(),
+	}
+}
+
+// newNotificationStyles creates the notification styles from the current config
+func newNotificationStyles() *NotificationStyles {
+	return &NotificationStyles{
 		successStyle: fromStyleConfig(config.AppConfig.General.LogInfoUI.Style),
 		infoStyle:    fromStyleConfig(config.AppConfig.General.LogInfoUI.Style),
 		warningStyle: fromStyleConfig(config.AppConfig.General.LogWarningUI.Style),
 		errorStyle:   fromStyleConfig(config.AppConfig.General.LogErrorUI.Style),
 	}
+}
 
-	return &NotificationModel{
-		activeNotification: nil,
-		isVisible:          true, // Default to visible
-		styles:             notificationStyle,
-	}
+// ReloadConfig rebuilds the notification styles from the current config
+func (m *NotificationModel) ReloadConfig() {
+	m.styles = newNotificationStyles()
 }
 
 // SetSize updates the model dimensions
@@ -97,6 +130,7 @@ func (m *NotificationModel) ShowNotification(notificationType NotificationType,
 	}
 
 	m.activeNotification = notification
+	m.addToHistory(notification)
 
 	// Auto-clear notifications
 	return tea.Tick(autoClearNotificationDuration, func(t time.Time) tea.Msg {
@@ -104,6 +138,47 @@ func (m *NotificationModel) ShowNotification(notificationType NotificationType,
 	})
 }
 
+// addToHistory adds the notification to the history, dropping the oldest one if it is full
+func (m *NotificationModel) addToHistory(notification *Notification) {
+	if len(m.history) >= maxNotificationHistory {
+		m.history = m.history[1:]
+	}
+
+	m.history = append(m.history, notification)
+
+	if notification.Type == NotificationError {
+		m.unreadErrors++
+	}
+}
+
+// GetHistory returns the notifications from the oldest to the newest
+func (m *NotificationModel) GetHistory() []*Notification {
+	return m.history
+}
+
+// GetUnreadErrorCount returns the number of errors which were not read in the history yet
+func (m *NotificationModel) GetUnreadErrorCount() int {
+	return m.unreadErrors
+}
+
+// MarkAllRead marks all the notifications of the history as read
+func (m *NotificationModel) MarkAllRead() {
+	m.unreadErrors = 0
+}
+
+// ExportHistory writes the history to the file at the given path, one notification per line
+func (m *NotificationModel) ExportHistory(path string) error {
+	const perm = 0600
+
+	var content strings.Builder
+	for _, notification := range m.history {
+		fmt.Fprintf(&content, "%s [%s] %s\n",
+			notification.CreatedAt.Format(time.DateTime), notification.Type, notification.Message)
+	}
+
+	return os.WriteFile(path, []byte(content.String()), perm)
+}
+
 // GetActiveNotification returns the current active notification
 func (m *NotificationModel) GetActiveNotification() *Notification {
 	return m.activeNotification
@@ -123,29 +198,7 @@ func (m *NotificationModel) View() string {
 		return ""
 	}
 
-	var notificationStyle lipgloss.Style
-	var prefix, suffix string
-	cfg := config.AppConfig.General
-
-	// Get style and prefix/suffix based on notification type
-	switch m.activeNotification.Type {
-	case NotificationSuccess:
-		notificationStyle = m.styles.successStyle
-		prefix = cfg.LogInfoUI.Prefix
-		suffix = cfg.LogInfoUI.Suffix
-	case NotificationInfo:
-		notificationStyle = m.styles.infoStyle
-		prefix = cfg.LogInfoUI.Prefix
-		suffix = cfg.LogInfoUI.Suffix
-	case NotificationWarning:
-		notificationStyle = m.styles.warningStyle
-		prefix = cfg.LogWarningUI.Prefix
-		suffix = cfg.LogWarningUI.Suffix
-	case NotificationError:
-		notificationStyle = m.styles.errorStyle
-		prefix = cfg.LogErrorUI.Prefix
-		suffix = cfg.LogErrorUI.Suffix
-	}
+	notificationStyle, prefix, suffix := m.GetStyle(m.activeNotification.Type)
 
 	// Format message with prefix and suffix
 	message := prefix + m.activeNotification.Message + suffix
@@ -155,3 +208,21 @@ func (m *NotificationModel) View() string {
 
 	return notificationStyle.Render(message)
 }
+
+// GetStyle returns the style, the prefix and the suffix of the notification type
+func (m *NotificationModel) GetStyle(
+	notificationType NotificationType,
+) (lipgloss.Style, string, string) {
+	cfg := config.AppConfig.General
+
+	switch notificationType {
+	case NotificationSuccess:
+		return m.styles.successStyle, cfg.LogInfoUI.Prefix, cfg.LogInfoUI.Suffix
+	case NotificationWarning:
+		return m.styles.warningStyle, cfg.LogWarningUI.Prefix, cfg.LogWarningUI.Suffix
+	case NotificationError:
+		return m.styles.errorStyle, cfg.LogErrorUI.Prefix, cfg.LogErrorUI.Suffix
+	default:
+		return m.styles.infoStyle, cfg.LogInfoUI.Prefix, cfg.LogInfoUI.Suffix
+	}
+}
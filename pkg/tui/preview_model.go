@@ -0,0 +1,294 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/fs"
+	"github.com/dinhhuy258/fm/pkg/preview"
+	"github.com/dinhhuy258/fm/pkg/types"
+)
+
+// Limits of the preview rendering
+const (
+	// previewCacheSize is the number of rendered previews kept in the cache
+	previewCacheSize = 32
+	// previewDelay is the delay before a preview is rendered, the entries which are only moved
+	// over are not rendered
+	previewDelay = 50 * time.Millisecond
+	// previewConcurrency is the maximum number of previews rendered at the same time
+	previewConcurrency = 2
+)
+
+// previewKey identifies a rendered preview, the preview is rendered again when the entry or the
+// size of the preview changes
+type previewKey struct {
+	path       string
+	size       int64
+	changeTime time.Time
+	width      int
+	height     int
+	protocol   types.ImageProtocol
+}
+
+// previewResult is a rendered preview or the error which prevented the rendering
+type previewResult struct {
+	preview preview.Preview
+	err     error
+}
+
+// PreviewModel shows the preview of the focused entry next to the explorer. Previews are
+// rendered in the background and cached.
+type PreviewModel struct {
+	width  int
+	height int
+
+	visible bool
+
+	protocol     types.ImageProtocol
+	maxImageSize int64
+
+	// key is the key of the shown preview, result is nil while it is rendered
+	key    previewKey
+	result *previewResult
+
+	// cancel drops the pending render of the shown preview, semaphore limits the number of
+	// renders running at the same time
+	cancel    context.CancelFunc
+	semaphore chan struct{}
+
+	cache      map[previewKey]*previewResult
+	cacheOrder []previewKey
+
+	// Styles
+	separatorStyle lipgloss.Style
+	messageStyle   lipgloss.Style
+	errorStyle     lipgloss.Style
+}
+
+// NewPreviewModel creates a new preview model
+func NewPreviewModel() *PreviewModel {
+	m := &PreviewModel{
+		cache:     make(map[previewKey]*previewResult),
+		semaphore: make(chan struct{}, previewConcurrency),
+	}
+	if previewConfig := config.AppConfig.General.Preview; previewConfig != nil {
+		m.visible = previewConfig.Visible
+	}
+	m.ReloadConfig()
+
+	return m
+}
+
+// ReloadConfig applies the image protocol and recreates the styles which depend on the config
+func (m *PreviewModel) ReloadConfig() {
+	generalConfig := config.AppConfig.General
+	frameColor, _ := getFrameColors()
+
+	m.protocol = types.ImageProtocolNone
+	m.maxImageSize = 0
+
+	if generalConfig.Preview != nil {
+		m.protocol = preview.ResolveImageProtocol(
+			types.ImageProtocol(generalConfig.Preview.ImageProtocol))
+		m.maxImageSize = int64(generalConfig.Preview.MaxImageSize)
+	}
+
+	m.separatorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(config.ParseColor(frameColor, false)))
+	m.messageStyle = fromStyleConfig(generalConfig.InfoStyle)
+	m.errorStyle = lipgloss.NewStyle()
+
+	if generalConfig.LogErrorUI != nil {
+		m.errorStyle = fromStyleConfig(generalConfig.LogErrorUI.Style)
+	}
+}
+
+// SetSize updates the preview size, including the separator
+func (m *PreviewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the preview
+func (m *PreviewModel) Show() {
+	m.visible = true
+}
+
+// Hide closes the preview
+func (m *PreviewModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the preview is visible
+func (m *PreviewModel) IsVisible() bool {
+	return m.visible
+}
+
+// Load shows the preview of an entry, it returns the command rendering the preview if it is not
+// in the cache. The pending render of the previous entry is cancelled.
+func (m *PreviewModel) Load(entry fs.IEntry) tea.Cmd {
+	if entry == nil {
+		m.cancelRender()
+		m.key = previewKey{}
+		m.result = &previewResult{}
+
+		return nil
+	}
+
+	key := previewKey{
+		path:       entry.GetPath(),
+		size:       entry.GetSize(),
+		changeTime: entry.GetChangeTime(),
+		width:      m.getContentWidth(),
+		height:     m.height,
+		protocol:   m.protocol,
+	}
+	if key == m.key {
+		return nil
+	}
+
+	m.cancelRender()
+	m.key = key
+	m.result = m.cache[key]
+
+	if m.result != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	return m.render(ctx, key)
+}
+
+// render returns the command rendering a preview after the delay, once a render slot is free.
+// The render is dropped if the context is cancelled before it starts.
+func (m *PreviewModel) render(ctx context.Context, key previewKey) tea.Cmd {
+	options := preview.Options{
+		Width:         key.width,
+		Height:        key.height,
+		ImageProtocol: key.protocol,
+		MaxImageSize:  m.maxImageSize,
+	}
+	semaphore := m.semaphore
+
+	return func() tea.Msg {
+		select {
+		case <-time.After(previewDelay):
+		case <-ctx.Done():
+			return nil
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+		case <-ctx.Done():
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		rendered, err := preview.Render(key.path, options)
+
+		return previewLoadedMessage{
+			key:    key,
+			result: &previewResult{preview: rendered, err: err},
+		}
+	}
+}
+
+// cancelRender drops the pending render of the shown preview
+func (m *PreviewModel) cancelRender() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+// SetResult caches a rendered preview and shows it if it is the preview of the focused entry
+func (m *PreviewModel) SetResult(key previewKey, result *previewResult) {
+	if _, exists := m.cache[key]; !exists {
+		if len(m.cacheOrder) == previewCacheSize {
+			delete(m.cache, m.cacheOrder[0])
+			m.cacheOrder = m.cacheOrder[1:]
+		}
+
+		m.cacheOrder = append(m.cacheOrder, key)
+	}
+
+	m.cache[key] = result
+
+	if key == m.key {
+		m.cancelRender()
+		m.result = result
+	}
+}
+
+// HasKittyImage returns whether the shown preview draws an image with the kitty graphics protocol
+func (m *PreviewModel) HasKittyImage() bool {
+	return m.result != nil && m.result.err == nil && m.result.preview.KittyImage
+}
+
+// ClearImages adds the deletion of the kitty images to every line of a view which doesn't show
+// the image. Only the changed lines are redrawn, so every line carries the deletion.
+func (m *PreviewModel) ClearImages(view string) string {
+	if m.protocol != types.ImageProtocolKitty {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = preview.KittyDeleteImages + line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// getContentWidth returns the width of the preview without the separator
+func (m *PreviewModel) getContentWidth() int {
+	return max(m.width-previewSeparatorWidth, 0)
+}
+
+// View renders the preview with the separator on its left
+func (m *PreviewModel) View() string {
+	var content string
+
+	switch {
+	case m.result == nil:
+		content = m.messageStyle.Render("Loading…")
+	case m.result.err != nil:
+		content = m.errorStyle.Render(m.result.err.Error())
+	default:
+		content = m.result.preview.Content
+	}
+
+	width := m.getContentWidth()
+	contentLines := strings.Split(content, "\n")
+	separator := m.separatorStyle.Render("│") + strings.Repeat(" ", previewSeparatorWidth-1)
+
+	lines := make([]string, m.height)
+	for i := range lines {
+		line := ""
+		if i < len(contentLines) {
+			line = contentLines[i]
+		}
+
+		lineWidth := lipgloss.Width(line)
+		if lineWidth > width {
+			line = lipgloss.NewStyle().MaxWidth(width).Render(line)
+			lineWidth = lipgloss.Width(line)
+		}
+
+		lines[i] = separator + line + strings.Repeat(" ", max(width-lineWidth, 0))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	set "github.com/deckarep/golang-set/v2"
+
+	"github.com/dinhhuy258/fm/pkg/actions"
+	"github.com/dinhhuy258/fm/pkg/config"
+)
+
+// selectionPanelRow is a line of the selection panel: a directory or a selected path in it
+type selectionPanelRow struct {
+	path  string
+	isDir bool
+}
+
+// SelectionPanelModel lists the selected paths of every directory grouped by directory.
+// Paths can be removed from the selection, jumped to, and the missing ones cleared.
+type SelectionPanelModel struct {
+	width  int
+	height int
+
+	visible bool
+
+	explorerModel *ExplorerModel
+
+	rows []selectionPanelRow
+	// pathRows contains the indexes of the rows of the selected paths
+	pathRows []int
+	// missing contains the selected paths which did not exist when the panel was shown
+	missing set.Set[string]
+	// cursor is the index of the focused path in pathRows
+	cursor int
+	// offset is the first visible row
+	offset int
+
+	// Styles
+	titleStyle       lipgloss.Style
+	instructionStyle lipgloss.Style
+	borderStyle      lipgloss.Style
+	directoryStyle   lipgloss.Style
+	focusStyle       lipgloss.Style
+	missingStyle     lipgloss.Style
+}
+
+// NewSelectionPanelModel creates a new selection panel model
+func NewSelectionPanelModel(explorerModel *ExplorerModel) *SelectionPanelModel {
+	m := &SelectionPanelModel{
+		explorerModel: explorerModel,
+		missing:       set.NewSet[string](),
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Align(lipgloss.Center),
+		directoryStyle: lipgloss.NewStyle().Bold(true),
+	}
+	m.ReloadConfig()
+
+	return m
+}
+
+// ReloadConfig recreates the styles which depend on the config
+func (m *SelectionPanelModel) ReloadConfig() {
+	_, selFrameColor := getFrameColors()
+
+	m.instructionStyle = fromStyleConfig(config.AppConfig.General.FooterStyle).
+		Align(lipgloss.Center)
+	m.borderStyle = newBorderStyle(selFrameColor)
+	m.focusStyle = fromStyleConfig(config.AppConfig.General.ExplorerTable.FocusUI.Style)
+	m.missingStyle = fromStyleConfig(config.AppConfig.General.LogErrorUI.Style)
+}
+
+// SetSize updates the panel size
+func (m *SelectionPanelModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.ensureVisible()
+}
+
+// Show displays the panel and checks which selected paths no longer exist
+func (m *SelectionPanelModel) Show() {
+	m.visible = true
+	m.cursor = 0
+	m.offset = 0
+	m.missing = set.NewSet[string]()
+
+	for _, path := range m.explorerModel.GetSelectedPaths() {
+		if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+			m.missing.Add(path)
+		}
+	}
+
+	m.Refresh()
+}
+
+// Hide closes the panel
+func (m *SelectionPanelModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the panel is visible
+func (m *SelectionPanelModel) IsVisible() bool {
+	return m.visible
+}
+
+// Refresh rebuilds the rows from the current selections
+func (m *SelectionPanelModel) Refresh() {
+	paths := m.explorerModel.GetSelectedPaths()
+	slices.Sort(paths)
+
+	m.rows = m.rows[:0]
+	m.pathRows = m.pathRows[:0]
+
+	for i, path := range paths {
+		dir := filepath.Dir(path)
+		if i == 0 || filepath.Dir(paths[i-1]) != dir {
+			m.rows = append(m.rows, selectionPanelRow{path: dir, isDir: true})
+		}
+
+		m.pathRows = append(m.pathRows, len(m.rows))
+		m.rows = append(m.rows, selectionPanelRow{path: path})
+	}
+
+	m.cursor = max(min(m.cursor, len(m.pathRows)-1), 0)
+	m.ensureVisible()
+}
+
+// Update handles the keys of the panel, it returns the command of the executed action
+func (m *SelectionPanelModel) Update(msg tea.KeyMsg) tea.Cmd {
+	if !m.visible {
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.Hide()
+	case "j", "down":
+		m.move(1)
+	case "k", "up":
+		m.move(-1)
+	case "g", "home":
+		m.move(-len(m.pathRows))
+	case "G", "end":
+		m.move(len(m.pathRows))
+	case "d", "x":
+		if path, ok := m.getFocusedPath(); ok {
+			m.explorerModel.DeselectPath(path)
+			m.Refresh()
+		}
+	case "enter":
+		if path, ok := m.getFocusedPath(); ok {
+			m.Hide()
+
+			return func() tea.Msg {
+				return actions.FocusPathMessage{Path: path}
+			}
+		}
+	case "c":
+		count := m.explorerModel.ClearMissingSelections()
+		m.Refresh()
+
+		return func() tea.Msg {
+			return actions.LogMessage{
+				Level:   actions.LogLevelInfo,
+				Message: fmt.Sprintf("Removed %d missing path(s) from the selection", count),
+			}
+		}
+	}
+
+	return nil
+}
+
+// move moves the cursor by delta paths
+func (m *SelectionPanelModel) move(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.pathRows)-1), 0)
+	m.ensureVisible()
+}
+
+// getFocusedPath returns the path under the cursor
+func (m *SelectionPanelModel) getFocusedPath() (string, bool) {
+	if m.cursor >= len(m.pathRows) {
+		return "", false
+	}
+
+	return m.rows[m.pathRows[m.cursor]].path, true
+}
+
+// getVisibleRows returns the number of rows which fit in the panel
+func (m *SelectionPanelModel) getVisibleRows() int {
+	// Account for border, title and instructions
+	return max(m.height-6, 1)
+}
+
+// ensureVisible scrolls so that the focused path and its directory are visible
+func (m *SelectionPanelModel) ensureVisible() {
+	if len(m.pathRows) == 0 {
+		m.offset = 0
+
+		return
+	}
+
+	row := m.pathRows[m.cursor]
+	visibleRows := m.getVisibleRows()
+
+	// Keep the directory of the first path of a group visible
+	top := row
+	if row > 0 && m.rows[row-1].isDir {
+		top = row - 1
+	}
+
+	if top < m.offset {
+		m.offset = top
+	} else if row >= m.offset+visibleRows {
+		m.offset = row - visibleRows + 1
+	}
+}
+
+// View renders the selection panel
+func (m *SelectionPanelModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	title := m.titleStyle.Render(fmt.Sprintf("Selection (%d)", len(m.pathRows)))
+
+	content := "No selected paths"
+	if len(m.rows) > 0 {
+		end := min(m.offset+m.getVisibleRows(), len(m.rows))
+
+		lines := make([]string, 0, end-m.offset)
+		for i := m.offset; i < end; i++ {
+			lines = append(lines, m.renderRow(i))
+		}
+
+		content = strings.Join(lines, "\n")
+	}
+
+	instructions := m.instructionStyle.Render(
+		"esc close • ↑↓ move • enter jump • d remove • c clear missing",
+	)
+
+	// Account for border and padding
+	innerStyle := lipgloss.NewStyle().MaxWidth(max(m.width-4, 1))
+
+	rendered := m.borderStyle.Render(innerStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		content,
+		instructions,
+	)))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		rendered,
+		lipgloss.WithWhitespaceChars(""),
+	)
+}
+
+// renderRow renders a directory or a selected path
+func (m *SelectionPanelModel) renderRow(idx int) string {
+	row := m.rows[idx]
+	if row.isDir {
+		return m.directoryStyle.Render(row.path)
+	}
+
+	line := "  " + filepath.Base(row.path)
+	if m.missing.Contains(row.path) {
+		line += m.missingStyle.Render(" (missing)")
+	}
+
+	if len(m.pathRows) > 0 && m.pathRows[m.cursor] == idx {
+		return m.focusStyle.Render(line)
+	}
+
+	return line
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+	"github.com/dinhhuy258/fm/pkg/fs"
+)
+
+// gitHashLength is the length of the abbreviated commit hash shown for a detached HEAD
+const gitHashLength = 7
+
+// StatusLine renders the segments of the header or the footer
+type StatusLine struct {
+	segments  []*config.StatusSegmentConfig
+	separator string
+
+	style         lipgloss.Style
+	segmentStyles []lipgloss.Style
+}
+
+// NewStatusLine creates a status line from its config, segments without style use the given style
+func NewStatusLine(
+	statusLineConfig *config.StatusLineConfig,
+	style *config.StyleConfig,
+) *StatusLine {
+	sl := &StatusLine{
+		style: fromStyleConfig(style),
+	}
+
+	if statusLineConfig == nil {
+		return sl
+	}
+
+	sl.separator = statusLineConfig.Separator
+
+	for _, segment := range statusLineConfig.Segments {
+		if segment == nil {
+			continue
+		}
+
+		segmentStyle := sl.style
+		if segment.Style != nil {
+			segmentStyle = fromStyleConfig(segment.Style)
+		}
+
+		sl.segments = append(sl.segments, segment)
+		sl.segmentStyles = append(sl.segmentStyles, segmentStyle)
+	}
+
+	return sl
+}
+
+// Render renders the segments whose placeholders are all set, separated by the separator
+func (sl *StatusLine) Render(getValue func(placeholder string) string) string {
+	rendered := make([]string, 0, len(sl.segments))
+
+	for i, segment := range sl.segments {
+		text, complete := config.ExpandPlaceholders(segment.Format, getValue)
+		if !complete {
+			continue
+		}
+
+		rendered = append(rendered, sl.segmentStyles[i].Render(text))
+	}
+
+	return strings.Join(rendered, sl.style.Render(sl.separator))
+}
+
+// getPlaceholderValue returns the value of a placeholder of the status lines, unknown
+// placeholders are kept as is
+func (m Model) getPlaceholderValue(placeholder string) string {
+	switch placeholder {
+	case config.PlaceholderPwd:
+		return m.currentPath
+	case config.PlaceholderMode:
+		return m.modeManager.GetCurrentMode()
+	case config.PlaceholderTotal:
+		total, _ := m.explorerModel.GetStats()
+
+		return strconv.Itoa(total)
+	case config.PlaceholderSelected:
+		return formatNonZero(len(m.explorerModel.GetSelectedPaths()))
+	case config.PlaceholderSelectedSize:
+		return m.getSelectedSize()
+	case config.PlaceholderSort:
+		if m.reverse {
+			return m.sortType.String() + " reverse"
+		}
+
+		return m.sortType.String()
+	case config.PlaceholderHidden:
+		if m.showHidden {
+			return "hidden"
+		}
+
+		return ""
+	case config.PlaceholderFreeSpace:
+		return m.getCachedDirectoryInfo(placeholder, func() string {
+			freeSpace, err := fs.GetFreeSpace(m.currentPath)
+			if err != nil {
+				return ""
+			}
+
+			return fs.Humanize(int64(freeSpace))
+		})
+	case config.PlaceholderGitBranch:
+		return m.getCachedDirectoryInfo(placeholder, func() string {
+			return getGitBranch(m.currentPath)
+		})
+	case config.PlaceholderFocusName:
+		if focusedEntry := m.explorerModel.GetFocusedEntry(); focusedEntry != nil {
+			return focusedEntry.GetName()
+		}
+
+		return ""
+	case config.PlaceholderCount:
+		return formatNonZero(m.keyManager.GetCount())
+	case config.PlaceholderUnreadErrors:
+		return formatNonZero(m.notificationModel.GetUnreadErrorCount())
+	default:
+		return "{" + placeholder + "}"
+	}
+}
+
+// getCachedDirectoryInfo returns the information about the current directory from the cache,
+// loading it on first use. The cache is cleared when a directory is loaded.
+func (m Model) getCachedDirectoryInfo(key string, load func() string) string {
+	if value, exists := m.directoryInfo[key]; exists {
+		return value
+	}
+
+	value := load()
+	m.directoryInfo[key] = value
+
+	return value
+}
+
+// getSelectedSize returns the total size of the selected files, empty if nothing is selected
+func (m Model) getSelectedSize() string {
+	paths := m.explorerModel.GetSelectedPaths()
+	if len(paths) == 0 {
+		return ""
+	}
+
+	var size int64
+
+	for _, path := range paths {
+		if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+	}
+
+	return fs.Humanize(size)
+}
+
+// formatNonZero formats a number, zero is formatted as an empty string
+func formatNonZero(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
+
+// getGitBranch returns the branch of the git repository containing the path, the abbreviated
+// commit hash for a detached HEAD, and an empty string outside a repository
+func getGitBranch(path string) string {
+	for dir := path; ; dir = filepath.Dir(dir) {
+		gitDir := filepath.Join(dir, ".git")
+
+		if info, err := os.Stat(gitDir); err == nil {
+			if !info.IsDir() {
+				// In worktrees and submodules .git is a file pointing to the git directory
+				gitDir = readGitDirFile(gitDir)
+			}
+
+			return readGitHead(gitDir)
+		}
+
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
+// readGitDirFile returns the git directory of a .git file
+func readGitDirFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	gitDir, found := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !found {
+		return ""
+	}
+
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+
+	return gitDir
+}
+
+// readGitHead returns the branch or the abbreviated commit of the HEAD of a git directory
+func readGitHead(gitDir string) string {
+	if gitDir == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+
+	head := strings.TrimSpace(string(content))
+	if ref, found := strings.CutPrefix(head, "ref: "); found {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+
+	if len(head) > gitHashLength {
+		return head[:gitHashLength]
+	}
+
+	return head
+}
@@ -4,108 +4,27 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/gookit/color"
 
 	"github.com/dinhhuy258/fm/pkg/config"
 )
 
-const (
-	// Hex color validation constants
-	shortHexLength = 4 // #RGB
-	longHexLength  = 7 // #RRGGBB
-	hexPrefix      = "#"
-)
-
-// ColorEntry represents a color with both foreground and background variants
-type ColorEntry struct {
-	Foreground string
-	Background string
-}
-
-// ensureHexPrefix adds # prefix to hex color if not present
-func ensureHexPrefix(hexColor string) string {
-	if strings.HasPrefix(hexColor, hexPrefix) {
-		return hexPrefix + hexColor
-	}
-
-	return hexPrefix + hexColor
-}
-
-// colorMap maps color names to their hex values
-var colorMap = map[string]ColorEntry{
-	"default": {ensureHexPrefix(color.FgWhite.RGB().Hex()), ensureHexPrefix(color.BgBlack.RGB().Hex())},
-	"black":   {ensureHexPrefix(color.FgBlack.RGB().Hex()), ensureHexPrefix(color.BgBlack.RGB().Hex())},
-	"red":     {ensureHexPrefix(color.FgRed.RGB().Hex()), ensureHexPrefix(color.BgRed.RGB().Hex())},
-	"green":   {ensureHexPrefix(color.FgGreen.RGB().Hex()), ensureHexPrefix(color.BgGreen.RGB().Hex())},
-	"yellow":  {ensureHexPrefix(color.FgYellow.RGB().Hex()), ensureHexPrefix(color.BgYellow.RGB().Hex())},
-	"blue":    {ensureHexPrefix(color.FgBlue.RGB().Hex()), ensureHexPrefix(color.BgBlue.RGB().Hex())},
-	"magenta": {ensureHexPrefix(color.FgMagenta.RGB().Hex()), ensureHexPrefix(color.BgMagenta.RGB().Hex())},
-	"cyan":    {ensureHexPrefix(color.FgCyan.RGB().Hex()), ensureHexPrefix(color.BgCyan.RGB().Hex())},
-	"white":   {ensureHexPrefix(color.FgWhite.RGB().Hex()), ensureHexPrefix(color.BgWhite.RGB().Hex())},
-}
-
-// isValidHexValue validates if a string is a valid hex color value.
-func isValidHexValue(hex string) bool {
-	if hex == "" {
-		return false
-	}
-
-	if len(hex) != shortHexLength && len(hex) != longHexLength {
-		return false
-	}
-
-	if !strings.HasPrefix(hex, hexPrefix) {
-		return false
-	}
-
-	// Validate hex characters
-	for _, char := range hex[1:] {
-		if !isHexChar(char) {
-			return false
-		}
-	}
-
-	return true
-}
-
-// isHexChar checks if a character is a valid hexadecimal digit
-func isHexChar(char rune) bool {
-	return (char >= '0' && char <= '9') ||
-		(char >= 'A' && char <= 'F') ||
-		(char >= 'a' && char <= 'f')
-}
-
-// parseColor converts a color string to a valid hex color.
-func parseColor(colorStr string, isBg bool) string {
-	if colorStr == "" {
-		return getDefaultColor(isBg)
-	}
-
-	// Check if it's a valid hex color
-	if isValidHexValue(colorStr) {
-		return colorStr
-	}
-
-	// Check if it's a named color
-	if colorEntry, exists := colorMap[strings.ToLower(colorStr)]; exists {
-		if isBg {
-			return colorEntry.Background
-		}
-
-		return colorEntry.Foreground
-	}
-
-	// Fall back to default color
-	return getDefaultColor(isBg)
+// newBorderStyle creates the rounded border style of the frames with the given color
+func newBorderStyle(color string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(config.ParseColor(color, false))).
+		PaddingLeft(1).
+		PaddingRight(1)
 }
 
-// getDefaultColor returns the default foreground or background color
-func getDefaultColor(isBg bool) string {
-	if isBg {
-		return colorMap["default"].Background
+// getFrameColors returns the color of the main frame and the color of the frames shown over it
+func getFrameColors() (string, string) {
+	frameUI := config.AppConfig.General.FrameUI
+	if frameUI == nil {
+		return "", ""
 	}
 
-	return colorMap["default"].Foreground
+	return frameUI.FrameColor, frameUI.SelFrameColor
 }
 
 // fromStyleConfig converts a config.StyleConfig to a lipgloss.Style.
@@ -117,8 +36,8 @@ func fromStyleConfig(styleConfig *config.StyleConfig) lipgloss.Style {
 	}
 
 	// Parse and store colors before applying decorations
-	fgColor := parseColor(styleConfig.Fg, false)
-	bgColor := parseColor(styleConfig.Bg, true)
+	fgColor := config.ParseColor(styleConfig.Fg, false)
+	bgColor := config.ParseColor(styleConfig.Bg, true)
 
 	// Set foreground and background colors
 	style = style.Foreground(lipgloss.Color(fgColor))
@@ -127,13 +46,13 @@ func fromStyleConfig(styleConfig *config.StyleConfig) lipgloss.Style {
 	// Apply decorations
 	for _, decoration := range styleConfig.Decorations {
 		switch strings.ToLower(decoration) {
-		case "bold":
+		case config.DecorationBold:
 			style = style.Bold(true)
-		case "italic":
+		case config.DecorationItalic:
 			style = style.Italic(true)
-		case "underline", "underscore":
+		case config.DecorationUnderline, config.DecorationUnderscore:
 			style = style.Underline(true)
-		case "reverse":
+		case config.DecorationReverse:
 			// For reverse, swap the already-parsed foreground and background colors
 			style = style.Foreground(lipgloss.Color(bgColor))
 			style = style.Background(lipgloss.Color(fgColor))
@@ -141,4 +60,4 @@ func fromStyleConfig(styleConfig *config.StyleConfig) lipgloss.Style {
 	}
 
 	return style
-}
\ No newline at end of file
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dinhhuy258/fm/pkg/config"
+)
+
+// showWhichKey shows the keys which can follow the pending keys after the which-key delay
+func (m Model) showWhichKey() tea.Cmd {
+	delay := config.AppConfig.General.WhichKeyDelay
+	if delay <= 0 {
+		return nil
+	}
+
+	sequenceID := m.keyManager.GetSequenceID()
+
+	return tea.Tick(time.Duration(delay)*time.Millisecond, func(time.Time) tea.Msg {
+		return whichKeyMessage{sequenceID: sequenceID}
+	})
+}
+
+// isWhichKeyVisible returns whether the which-key popup is shown for the pending keys
+func (m Model) isWhichKeyVisible() bool {
+	return m.keyManager.HasPendingKeys() &&
+		m.whichKeySequenceID == m.keyManager.GetSequenceID()
+}
+
+// renderWhichKey renders the popup listing the keys which can follow the pending keys, it fits
+// in the explorer table
+func (m Model) renderWhichKey() string {
+	width, height := m.explorerModel.GetSize()
+	keymaps := m.keyManager.GetNextKeyMaps()
+
+	// Account for the border and the title
+	maxRows := max(height-3, 1)
+	if len(keymaps) > maxRows {
+		hidden := len(keymaps) - maxRows + 1
+		keymaps = append(keymaps[:maxRows-1], keyMapEntry{
+			description: fmt.Sprintf("… %d more", hidden),
+		})
+	}
+
+	keyWidth := 0
+	for _, keymap := range keymaps {
+		keyWidth = max(keyWidth, lipgloss.Width(keymap.key))
+	}
+
+	keyStyle := lipgloss.NewStyle().Bold(true)
+	lines := []string{keyStyle.Render(m.keyManager.GetPendingKeys() + " …")}
+
+	for _, keymap := range keymaps {
+		padding := strings.Repeat(" ", keyWidth-lipgloss.Width(keymap.key))
+		lines = append(lines, keyStyle.Render(keymap.key)+padding+"  "+keymap.description)
+	}
+
+	// Account for the border and the padding
+	content := lipgloss.NewStyle().
+		Width(max(width-4, 1)).
+		MaxWidth(max(width-4, 1)).
+		Render(strings.Join(lines, "\n"))
+
+	return m.whichKeyStyle.Render(content)
+}
+
+// overlayBottom replaces the last lines of the content with the lines of the popup
+func overlayBottom(content string, popup string) string {
+	lines := strings.Split(content, "\n")
+	popupLines := strings.Split(popup, "\n")
+	start := max(len(lines)-len(popupLines), 0)
+
+	return strings.Join(append(lines[:start], popupLines...), "\n")
+}
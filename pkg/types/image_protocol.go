@@ -0,0 +1,21 @@
+package types
+
+// ImageProtocol represents the way images are drawn in the terminal
+type ImageProtocol string
+
+const (
+	// ImageProtocolAuto uses the kitty graphics protocol if the terminal supports it and
+	// half blocks otherwise
+	ImageProtocolAuto ImageProtocol = "auto"
+	// ImageProtocolKitty draws images with the kitty graphics protocol
+	ImageProtocolKitty ImageProtocol = "kitty"
+	// ImageProtocolHalfBlock draws images with colored Unicode half blocks
+	ImageProtocolHalfBlock ImageProtocol = "half_block"
+	// ImageProtocolNone disables the preview of images
+	ImageProtocolNone ImageProtocol = "none"
+)
+
+// String returns the string representation of the image protocol
+func (p ImageProtocol) String() string {
+	return string(p)
+}